@@ -0,0 +1,258 @@
+package convoy_go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoCredentials  = errors.New("no credentials for request")
+	ErrNotRefreshable = errors.New("credentials can't be refreshed")
+)
+
+// DefaultTokenRefreshSkew is how long before it expires a RefreshingToken
+// fetches a new token.
+var DefaultTokenRefreshSkew = 30 * time.Second
+
+// Authenticator sets the credentials of each API call. It must be safe for
+// concurrent use.
+type Authenticator interface {
+	// Authenticate sets req's credentials, e.g. its Authorization header.
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// Refresher is implemented by Authenticators whose credentials can expire.
+// When Convoy answers a call with 401, the client calls Refresh with the
+// rejected request and sends it once more, unless Refresh returns
+// ErrNotRefreshable.
+type Refresher interface {
+	Refresh(ctx context.Context, req *http.Request) error
+}
+
+// OptionAuthenticator sets the credentials of the client's API calls,
+// instead of the API key passed to New.
+func OptionAuthenticator(a Authenticator) func(c *Client) {
+	return func(c *Client) {
+		c.auth = a
+	}
+}
+
+// AuthenticatorFunc lets a function select the credentials of each call,
+// e.g. from values in ctx.
+type AuthenticatorFunc func(ctx context.Context, req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, req *http.Request) error {
+	return f(ctx, req)
+}
+
+// BearerToken authenticates with a static API key or personal access token.
+type BearerToken string
+
+func (t BearerToken) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// PortalLinkToken authenticates with a portal link's token, which Convoy
+// scopes to the link's owner. Portal tokens are only accepted by the portal
+// API, served under /portal-api; on those routes OwnerID is sent as the
+// owner_id query parameter.
+type PortalLinkToken struct {
+	Token   string
+	OwnerID string
+}
+
+func (t *PortalLinkToken) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+t.Token)
+
+	if !isStringEmpty(t.OwnerID) && isPortalAPIPath(req.URL.Path) {
+		q := req.URL.Query()
+		q.Set("owner_id", t.OwnerID)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	return nil
+}
+
+// isPortalAPIPath reports whether path is a portal API route.
+func isPortalAPIPath(path string) bool {
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if seg == "portal-api" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TokenFunc fetches a bearer token, e.g. a JWT from a secret manager, and
+// the time it expires. A zero expiry means it doesn't.
+type TokenFunc func(ctx context.Context) (token string, expiresAt time.Time, err error)
+
+// RefreshingToken authenticates with tokens from a TokenFunc. Each token is
+// used until DefaultTokenRefreshSkew before it expires, or until Convoy
+// rejects it.
+type RefreshingToken struct {
+	fetch TokenFunc
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func NewRefreshingToken(fetch TokenFunc) *RefreshingToken {
+	return &RefreshingToken{fetch: fetch}
+}
+
+func (t *RefreshingToken) Authenticate(ctx context.Context, req *http.Request) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if isStringEmpty(t.token) ||
+		(!t.expiresAt.IsZero() && time.Until(t.expiresAt) < DefaultTokenRefreshSkew) {
+		err := t.refresh(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return nil
+}
+
+// Refresh fetches a new token, unless req was sent with an older one, so
+// concurrent calls rejected with the same token fetch it only once.
+func (t *RefreshingToken) Refresh(ctx context.Context, req *http.Request) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if req.Header.Get("Authorization") != "Bearer "+t.token {
+		return nil
+	}
+
+	return t.refresh(ctx)
+}
+
+// refresh fetches a new token. t.mu must be held.
+func (t *RefreshingToken) refresh(ctx context.Context) error {
+	token, expiresAt, err := t.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching token - %w", err)
+	}
+
+	t.token, t.expiresAt = token, expiresAt
+	return nil
+}
+
+// ProjectAuthenticator selects credentials by the project in each call's
+// path, so clients for several projects, or calls like Projects.Find for
+// another project, use that project's credentials. Projects must not be
+// modified once the authenticator is in use.
+type ProjectAuthenticator struct {
+	// Projects maps project IDs to their credentials.
+	Projects map[string]Authenticator
+
+	// Default authenticates calls to projects missing from Projects. When
+	// nil, those calls fail with ErrNoCredentials.
+	Default Authenticator
+}
+
+func (p *ProjectAuthenticator) Authenticate(ctx context.Context, req *http.Request) error {
+	a, err := p.authenticator(req)
+	if err != nil {
+		return err
+	}
+
+	return a.Authenticate(ctx, req)
+}
+
+// Refresh refreshes the credentials selected for req, or returns
+// ErrNotRefreshable when they aren't a Refresher.
+func (p *ProjectAuthenticator) Refresh(ctx context.Context, req *http.Request) error {
+	a, err := p.authenticator(req)
+	if err != nil {
+		return err
+	}
+
+	if r, ok := a.(Refresher); ok {
+		return r.Refresh(ctx, req)
+	}
+
+	return ErrNotRefreshable
+}
+
+func (p *ProjectAuthenticator) authenticator(req *http.Request) (Authenticator, error) {
+	if a, ok := p.Projects[projectIDFromPath(req.URL.Path)]; ok {
+		return a, nil
+	}
+
+	if p.Default != nil {
+		return p.Default, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNoCredentials, req.URL.Path)
+}
+
+// projectIDFromPath returns the ID following "projects" in path.
+func projectIDFromPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "projects" {
+			return segments[i+1]
+		}
+	}
+
+	return ""
+}
+
+// send authenticates req and sends it. When Convoy rejects the credentials
+// and they can be refreshed, req is sent once more with fresh ones.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	err := c.auth.Authenticate(req.Context(), req)
+	if err != nil {
+		return nil, fmt.Errorf("error authenticating request - %w", err)
+	}
+
+	resp, err := c.roundTrip(req)
+	if err != nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	refresher, ok := c.auth.(Refresher)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	err = refresher.Refresh(req.Context(), req)
+	if errors.Is(err, ErrNotRefreshable) {
+		return resp, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("error refreshing credentials - %w", err)
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.auth.Authenticate(retry.Context(), retry)
+	if err != nil {
+		return nil, fmt.Errorf("error authenticating request - %w", err)
+	}
+
+	return c.roundTrip(retry)
+}
@@ -0,0 +1,167 @@
+package convoy_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshingTokenRetriesAfterUnauthorized(t *testing.T) {
+	var auths, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		auths = append(auths, r.Header.Get("Authorization"))
+		bodies = append(bodies, string(b))
+
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"status":false,"message":"invalid token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"ev-1"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	fetches := 0
+	token := NewRefreshingToken(func(ctx context.Context) (string, time.Time, error) {
+		fetches++
+		return fmt.Sprintf("token-%d", fetches), time.Time{}, nil
+	})
+
+	c := New(srv.URL, "", "test-project-id", OptionAuthenticator(token))
+
+	ev, err := c.Events.Create(context.Background(), &CreateEventRequest{
+		EndpointID: "ep-1",
+		EventType:  "invoice.paid",
+		Data:       json.RawMessage(`{"id":1}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ev-1", ev.UID)
+
+	require.Equal(t, 2, fetches)
+	require.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, auths)
+	require.Equal(t, bodies[0], bodies[1])
+	require.Contains(t, bodies[1], `"event_type":"invoice.paid"`)
+}
+
+func TestUnauthorizedWithoutRefresherIsReturned(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"status":false,"message":"invalid api key"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(srv.URL, "test-api-key", "test-project-id")
+
+	_, err := c.Endpoints.Find(context.Background(), "ep-1", nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	require.Equal(t, 1, calls)
+}
+
+func TestProjectAuthenticatorDoesNotRetryStaticCredentials(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"status":false,"message":"invalid api key"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	auth := &ProjectAuthenticator{Projects: map[string]Authenticator{"project-a": BearerToken("key-a")}}
+	c := New(srv.URL, "", "project-a", OptionAuthenticator(auth))
+
+	_, err := c.Endpoints.Find(context.Background(), "ep-1", nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	require.Equal(t, "invalid api key", apiErr.Message)
+	require.Equal(t, 1, calls)
+}
+
+func TestRefreshingToken(t *testing.T) {
+	fetches := 0
+	var fetchErr error
+	token := NewRefreshingToken(func(ctx context.Context) (string, time.Time, error) {
+		if fetchErr != nil {
+			return "", time.Time{}, fetchErr
+		}
+		fetches++
+		// Within DefaultTokenRefreshSkew, so every call fetches a new one.
+		return fmt.Sprintf("token-%d", fetches), time.Now().Add(time.Second), nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/p1/endpoints", nil)
+	require.NoError(t, token.Authenticate(context.Background(), req))
+	require.Equal(t, "Bearer token-1", req.Header.Get("Authorization"))
+
+	stale := req.Clone(context.Background())
+	require.NoError(t, token.Authenticate(context.Background(), req))
+	require.Equal(t, "Bearer token-2", req.Header.Get("Authorization"))
+
+	// A call rejected with an older token doesn't fetch again.
+	require.NoError(t, token.Refresh(context.Background(), stale))
+	require.Equal(t, 2, fetches)
+
+	fetchErr = errors.New("vault sealed")
+	err := token.Refresh(context.Background(), req)
+	require.ErrorIs(t, err, fetchErr)
+}
+
+func TestProjectAuthenticator(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"p"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	auth := &ProjectAuthenticator{Projects: map[string]Authenticator{
+		"project-a": BearerToken("key-a"),
+		"project-b": BearerToken("key-b"),
+	}}
+	c := New(srv.URL, "", "project-a", OptionAuthenticator(auth))
+
+	_, err := c.Endpoints.Find(context.Background(), "ep-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, "Bearer key-a", got)
+
+	_, err = c.Projects.Find(context.Background(), "project-b")
+	require.NoError(t, err)
+	require.Equal(t, "Bearer key-b", got)
+
+	_, err = c.Projects.Find(context.Background(), "project-c")
+	require.ErrorIs(t, err, ErrNoCredentials)
+
+	auth.Default = BearerToken("key-default")
+	_, err = c.Projects.Find(context.Background(), "project-c")
+	require.NoError(t, err)
+	require.Equal(t, "Bearer key-default", got)
+}
+
+func TestPortalLinkToken(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/portal-api/endpoints?perPage=10", nil)
+
+	auth := &PortalLinkToken{Token: "portal-token", OwnerID: "owner-1"}
+	require.NoError(t, auth.Authenticate(context.Background(), req))
+
+	require.Equal(t, "Bearer portal-token", req.Header.Get("Authorization"))
+	require.Equal(t, "owner-1", req.URL.Query().Get("owner_id"))
+	require.Equal(t, "10", req.URL.Query().Get("perPage"))
+
+	// Project routes don't take portal tokens' owner scoping.
+	req = httptest.NewRequest(http.MethodGet, "/projects/p1/endpoints", nil)
+	require.NoError(t, auth.Authenticate(context.Background(), req))
+	require.Empty(t, req.URL.RawQuery)
+}
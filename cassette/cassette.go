@@ -0,0 +1,466 @@
+// Package cassette records the client's HTTP traffic to a file and replays
+// it offline, for contract tests that run against a real Convoy once and in
+// CI without one.
+//
+//	rec, err := cassette.New("testdata/endpoints.json", &cassette.Options{Mode: mode})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Save()
+//
+//	c := convoy.New(baseURL, apiKey, projectID, convoy.OptionTransport(rec))
+//
+// Requests are matched on method, path, query and JSON body, with object
+// keys sorted, so replays don't depend on the host or field order. Each
+// recorded interaction is replayed once, in order. A request with no
+// matching interaction fails with ErrUnmatchedRequest.
+//
+// The API key, endpoint secrets and other credentials are scrubbed before
+// anything is written, and scrubbed the same way before matching.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+)
+
+var (
+	ErrUnmatchedRequest = errors.New("cassette: no recorded interaction matches request")
+	ErrNoCassette       = errors.New("cassette: file not found")
+)
+
+// Redacted replaces scrubbed values.
+const Redacted = "[REDACTED]"
+
+var (
+	// DefaultScrubKeys are JSON keys whose values are scrubbed from request
+	// and response bodies, on top of convoy.DefaultRedactKeys, which the
+	// client redacts from its logs. The value of every "secrets" entry is
+	// scrubbed too.
+	DefaultScrubKeys []string
+	// DefaultScrubHeaders are headers whose values are scrubbed, on top of
+	// convoy.DefaultRedactHeaders.
+	DefaultScrubHeaders []string
+)
+
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette and never touches the
+	// network.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests through Options.Transport and records them.
+	// Save writes the cassette.
+	ModeRecord
+)
+
+type Options struct {
+	Mode Mode
+	// Transport sends requests in ModeRecord. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+	// Scrub lists more strings to replace wherever they appear, e.g. an
+	// endpoint secret your test generated.
+	Scrub []string
+	// IgnoreFields are JSON body keys left out of matching, e.g.
+	// "idempotency_key" when keys are generated per run.
+	IgnoreFields []string
+}
+
+// Cassette is the file format: the recorded interactions, in order.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body"`
+}
+
+// Body keeps JSON bodies readable in the cassette; anything else is stored
+// as a string.
+type Body struct {
+	JSON json.RawMessage `json:"json,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+func newBody(b []byte) Body {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return Body{}
+	}
+
+	if json.Valid(b) {
+		return Body{JSON: json.RawMessage(b)}
+	}
+
+	return Body{Text: string(b)}
+}
+
+func (b Body) bytes() []byte {
+	if b.JSON != nil {
+		return b.JSON
+	}
+
+	return []byte(b.Text)
+}
+
+// Recorder is an http.RoundTripper that records or replays a cassette.
+type Recorder struct {
+	path string
+	opts Options
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+	err      error
+}
+
+// New opens the cassette at path. In ModeReplay the file must exist; in
+// ModeRecord it's overwritten by Save.
+func New(path string, opts *Options) (*Recorder, error) {
+	r := &Recorder{path: path}
+	if opts != nil {
+		r.opts = *opts
+	}
+
+	if r.opts.Transport == nil {
+		r.opts.Transport = http.DefaultTransport
+	}
+
+	if r.opts.Mode == ModeRecord {
+		return r, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s; record it first with ModeRecord", ErrNoCassette, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(b, &r.cassette)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: invalid file %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers mustn't modify the request; the body is read from a
+	// clone's.
+	req = req.Clone(req.Context())
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.opts.Mode == ModeRecord {
+		return r.record(req, body)
+	}
+
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.opts.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := r.secrets(req)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.Query().Encode(),
+			Header: scrubHeader(req.Header),
+			Body:   newBody(scrub(body, secrets)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     responseHeader(resp.Header),
+			Body:       newBody(scrub(respBody, secrets)),
+		},
+	})
+
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	key := r.matchKey(req.Method, req.URL.Path, req.URL.Query().Encode(), scrub(body, r.secrets(req)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] {
+			continue
+		}
+
+		rec := in.Request
+		if r.matchKey(rec.Method, rec.Path, rec.Query, rec.Body.bytes()) != key {
+			continue
+		}
+
+		r.used[i] = true
+		return &http.Response{
+			StatusCode:    in.Response.StatusCode,
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body.bytes())),
+			ContentLength: int64(len(in.Response.Body.bytes())),
+			Request:       req,
+		}, nil
+	}
+
+	err := fmt.Errorf("%w: %s %s", ErrUnmatchedRequest, req.Method, req.URL.RequestURI())
+	if len(body) > 0 {
+		err = fmt.Errorf("%w with body %s", err, truncate(string(scrub(body, r.secrets(req))), 256))
+	}
+	if r.err == nil {
+		r.err = err
+	}
+
+	return nil, err
+}
+
+// Err returns the first unmatched request, so tests can fail even when
+// the code under test swallowed the client's error.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// Unused returns the recorded interactions that weren't replayed.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []Interaction
+	for i, used := range r.used {
+		if !used {
+			out = append(out, r.cassette.Interactions[i])
+		}
+	}
+
+	return out
+}
+
+// Save writes the recorded cassette. It does nothing in ModeReplay.
+func (r *Recorder) Save() error {
+	if r.opts.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(r.path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+// secrets lists the literal strings scrubbed from a request's
+// interaction: the bearer token it carries and Options.Scrub.
+func (r *Recorder) secrets(req *http.Request) []string {
+	secrets := append([]string{}, r.opts.Scrub...)
+
+	if token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "); token != "" && token != Redacted {
+		secrets = append(secrets, token)
+	}
+
+	return secrets
+}
+
+// matchKey canonicalizes a request for matching. JSON bodies are
+// re-encoded, which sorts object keys, after dropping IgnoreFields.
+func (r *Recorder) matchKey(method, path, query string, body []byte) string {
+	if v, ok := decodeJSON(body); ok {
+		dropFields(v, r.opts.IgnoreFields)
+		if b, err := json.Marshal(v); err == nil {
+			body = b
+		}
+	}
+
+	return method + " " + path + "?" + query + "\n" + string(bytes.TrimSpace(body))
+}
+
+// decodeJSON decodes a JSON body, keeping numbers as written.
+func decodeJSON(b []byte) (interface{}, bool) {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if dec.Decode(&v) != nil {
+		return nil, false
+	}
+
+	return v, true
+}
+
+func dropFields(v interface{}, fields []string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, f := range fields {
+			delete(t, f)
+		}
+		for _, x := range t {
+			dropFields(x, fields)
+		}
+	case []interface{}:
+		for _, x := range t {
+			dropFields(x, fields)
+		}
+	}
+}
+
+// scrub replaces credentials in a body: the values of DefaultScrubKeys,
+// convoy.DefaultRedactKeys and "secrets" entries in JSON bodies, and the
+// given literals anywhere.
+func scrub(body []byte, secrets []string) []byte {
+	if v, ok := decodeJSON(body); ok {
+		v = scrubJSON(v, "")
+		if b, err := json.Marshal(v); err == nil {
+			body = b
+		}
+	}
+
+	for _, s := range secrets {
+		if s != "" {
+			body = bytes.ReplaceAll(body, []byte(s), []byte(Redacted))
+		}
+	}
+
+	return body
+}
+
+func scrubJSON(v interface{}, parent string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, x := range t {
+			_, isString := x.(string)
+			if isString && (isScrubKey(k) || (parent == "secrets" && k == "value")) {
+				t[k] = Redacted
+				continue
+			}
+			t[k] = scrubJSON(x, k)
+		}
+	case []interface{}:
+		for i, x := range t {
+			t[i] = scrubJSON(x, parent)
+		}
+	}
+
+	return v
+}
+
+func isScrubKey(k string) bool {
+	for _, keys := range [][]string{convoy.DefaultRedactKeys, DefaultScrubKeys} {
+		for _, key := range keys {
+			if strings.EqualFold(k, key) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func scrubHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, names := range [][]string{convoy.DefaultRedactHeaders, DefaultScrubHeaders} {
+		for _, name := range names {
+			if out.Get(name) != "" {
+				out.Set(name, Redacted)
+			}
+		}
+	}
+
+	return out
+}
+
+// responseHeader scrubs a response's headers and drops Content-Length,
+// which scrubbing can make wrong.
+func responseHeader(h http.Header) http.Header {
+	out := scrubHeader(h)
+	out.Del("Content-Length")
+
+	return out
+}
+
+// readBody reads a body and puts back an unread copy.
+func readBody(rc *io.ReadCloser) ([]byte, error) {
+	if *rc == nil || *rc == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(*rc)
+	if err != nil {
+		return nil, err
+	}
+	(*rc).Close()
+
+	*rc = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	return s[:n] + "..."
+}
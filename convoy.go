@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/go-querystring/query"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type APIResponse struct {
@@ -35,23 +36,33 @@ type ListParams struct {
 
 type Client struct {
 	client    *http.Client
+	transport http.RoundTripper
 	baseURL   string
-	apiKey    string
+	auth      Authenticator
 	projectID string
-	log       iLogger
+	log       Logger
 	kafkaOpts *KafkaOptions
 	sqsOpts   *SQSOptions
-
-	Projects         *Project
-	Endpoints        *Endpoint
-	Events           *Event
-	EventDeliveries  *EventDelivery
-	DeliveryAttempts *DeliveryAttempt
-	Sources          *Source
-	Subscriptions    *Subscription
-	PortalLinks      *PortalLink
-	Kafka            *Kafka
-	SQS              *SQS
+	spool     *Spool
+	tracer    trace.Tracer
+	metrics   Metrics
+
+	middleware []Middleware
+	roundTrip  RoundTripFunc
+
+	idempotency *idempotency
+	limiter     *rateLimiter
+
+	Projects         ProjectService
+	Endpoints        EndpointService
+	Events           EventService
+	EventDeliveries  EventDeliveryService
+	DeliveryAttempts DeliveryAttemptService
+	Sources          SourceService
+	Subscriptions    SubscriptionService
+	PortalLinks      PortalLinkService
+	Kafka            BrokerService
+	SQS              BrokerService
 }
 
 type Option func(*Client)
@@ -68,7 +79,18 @@ func OptionSQSOptions(so *SQSOptions) func(c *Client) {
 	}
 }
 
-func OptionLogger(logger iLogger) func(c *Client) {
+// OptionSpool makes event creation, through the API or a broker, append the
+// event to s instead of dropping it when Convoy can't be reached. Use a
+// Replayer to send spooled events once it recovers.
+func OptionSpool(s *Spool) func(c *Client) {
+	return func(c *Client) {
+		c.spool = s
+	}
+}
+
+// OptionLogger sets the client's logger. API calls are logged at debug
+// level, with credentials redacted; see DefaultRedactKeys.
+func OptionLogger(logger Logger) func(c *Client) {
 	return func(c *Client) {
 		c.log = logger
 	}
@@ -80,13 +102,23 @@ func OptionHTTPClient(client *http.Client) func(c *Client) {
 	}
 }
 
+// OptionTransport sends the client's requests through rt, e.g. a cassette
+// recorder. It applies to the client given with OptionHTTPClient as well,
+// without modifying it.
+func OptionTransport(rt http.RoundTripper) func(c *Client) {
+	return func(c *Client) {
+		c.transport = rt
+	}
+}
+
 func New(baseURL, apiKey, projectID string, options ...Option) *Client {
 	c := &Client{
 		client: &http.Client{
 			Timeout: 5 * time.Second,
 		},
 		log:       NewLogger(os.Stdout, ErrorLevel),
-		apiKey:    apiKey,
+		metrics:   NopMetrics{},
+		auth:      BearerToken(apiKey),
 		projectID: projectID,
 		baseURL:   baseURL,
 	}
@@ -95,6 +127,14 @@ func New(baseURL, apiKey, projectID string, options ...Option) *Client {
 		opt(c)
 	}
 
+	if c.transport != nil {
+		hc := *c.client
+		hc.Transport = c.transport
+		c.client = &hc
+	}
+
+	c.roundTrip = c.chain()
+
 	c.Projects = newProject(c)
 	c.Endpoints = newEndpoint(c)
 	c.Events = newEvent(c)
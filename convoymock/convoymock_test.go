@@ -0,0 +1,84 @@
+package convoymock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMocksReplaceClientServices(t *testing.T) {
+	ctx := context.Background()
+
+	endpoints := &EndpointService{
+		FindFunc: func(ctx context.Context, endpointID string, query *convoy.EndpointParams) (*convoy.EndpointResponse, error) {
+			return &convoy.EndpointResponse{UID: endpointID, Status: convoy.EndpointStatusPaused}, nil
+		},
+	}
+
+	c := convoy.New("https://convoy.invalid/api/v1", "key", "project")
+	c.Endpoints = endpoints
+
+	ep, err := c.Endpoints.Find(ctx, "ep-1", nil)
+	require.NoError(t, err)
+	require.Equal(t, convoy.EndpointStatusPaused, ep.Status)
+
+	_, err = c.Endpoints.Pause(ctx, "ep-1")
+	require.ErrorIs(t, err, ErrNotMocked)
+	require.ErrorContains(t, err, "EndpointService.Pause")
+
+	calls := endpoints.Calls()
+	require.Len(t, calls, 2)
+	require.Equal(t, "Find", calls[0].Method)
+	require.Equal(t, "ep-1", calls[0].Args[1])
+	require.Len(t, endpoints.Calls("Pause"), 1)
+}
+
+func TestMocksRecordVariadicArgs(t *testing.T) {
+	var got []convoy.BatchOption
+	events := &EventService{
+		BatchReplayFunc: func(ctx context.Context, query *convoy.BatchReplayOptions, opts ...convoy.BatchOption) error {
+			got = opts
+			return nil
+		},
+	}
+
+	opt := convoy.WithConfirm(func(count int) bool { return true })
+	err := events.BatchReplay(context.Background(), nil, opt)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Len(t, events.Calls()[0].Args[2], 1)
+}
+
+func TestMocksDriveSecretRotation(t *testing.T) {
+	ctx := context.Background()
+
+	var secret string
+	endpointWith := func(id string) *convoy.EndpointResponse {
+		return &convoy.EndpointResponse{UID: id, AdvancedSignatures: true, Secrets: []convoy.Secret{
+			{Value: "old-secret", ExpiresAt: time.Now().Add(time.Hour)},
+			{Value: secret},
+		}}
+	}
+
+	endpoints := &EndpointService{
+		ExpireSecretFunc: func(ctx context.Context, id string, body *convoy.RollSecretRequest) (*convoy.EndpointResponse, error) {
+			secret = body.Secret
+			return endpointWith(id), nil
+		},
+		FindFunc: func(ctx context.Context, id string, query *convoy.EndpointParams) (*convoy.EndpointResponse, error) {
+			return endpointWith(id), nil
+		},
+	}
+
+	c := convoy.New("https://convoy.invalid/api/v1", "key", "project")
+	c.Endpoints = endpoints
+
+	rotation, err := convoy.NewSecretRotator(c, nil).Rotate(ctx, "ep-1")
+	require.NoError(t, err)
+	require.Equal(t, secret, rotation.New.Value)
+	require.Len(t, rotation.Old, 1)
+	require.Len(t, endpoints.Calls("ExpireSecret"), 1)
+}
@@ -0,0 +1,342 @@
+// Command mockgen writes convoymock's mocks: one struct per *Service
+// interface in the convoy package, with a Func field per method.
+//
+//	go run ./internal/mockgen -src .. -out mocks.gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const convoyPath = "github.com/frain-dev/convoy-go/v2"
+
+func main() {
+	src := flag.String("src", "..", "directory of the convoy package")
+	out := flag.String("out", "mocks.gen.go", "file to write")
+	flag.Parse()
+
+	b, err := generate(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(*out, b, 0o644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+type service struct {
+	name    string
+	methods []method
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []string
+}
+
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+// generate parses the convoy package in dir and returns the mocks file.
+func generate(dir string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []service
+	imports := map[string]string{"convoy": convoyPath}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			paths := fileImports(file)
+
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					iface, ok := ts.Type.(*ast.InterfaceType)
+					if !ok || !ts.Name.IsExported() || !strings.HasSuffix(ts.Name.Name, "Service") {
+						continue
+					}
+
+					svc, used, err := newService(fset, ts.Name.Name, iface)
+					if err != nil {
+						return nil, err
+					}
+
+					for _, name := range used {
+						path, ok := paths[name]
+						if !ok {
+							return nil, fmt.Errorf("%s: unknown package %s", ts.Name.Name, name)
+						}
+						imports[name] = path
+					}
+
+					services = append(services, svc)
+				}
+			}
+		}
+	}
+
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no service interfaces in %s", dir)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].name < services[j].name
+	})
+
+	var buf bytes.Buffer
+	writeFile(&buf, imports, services)
+
+	return format.Source(buf.Bytes())
+}
+
+func fileImports(file *ast.File) map[string]string {
+	paths := map[string]string{}
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		paths[name] = path
+	}
+
+	return paths
+}
+
+// newService collects an interface's methods with their types qualified for
+// use outside the convoy package, and the packages those types refer to.
+func newService(fset *token.FileSet, name string, iface *ast.InterfaceType) (service, []string, error) {
+	svc := service{name: name}
+	var used []string
+
+	typeString := func(expr ast.Expr) (string, error) {
+		expr = qualify(expr, &used)
+
+		var buf bytes.Buffer
+		err := printer.Fprint(&buf, fset, expr)
+		return buf.String(), err
+	}
+
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return svc, nil, fmt.Errorf("%s: embedded interfaces aren't supported", name)
+		}
+
+		m := method{name: field.Names[0].Name}
+
+		for _, p := range ft.Params.List {
+			typ := p.Type
+			variadic := false
+			if e, ok := typ.(*ast.Ellipsis); ok {
+				typ, variadic = e.Elt, true
+			}
+
+			s, err := typeString(typ)
+			if err != nil {
+				return svc, nil, err
+			}
+
+			names := p.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("arg%d", len(m.params)))}
+			}
+			for _, n := range names {
+				m.params = append(m.params, param{name: n.Name, typ: s, variadic: variadic})
+			}
+		}
+
+		if ft.Results != nil {
+			for _, r := range ft.Results.List {
+				s, err := typeString(r.Type)
+				if err != nil {
+					return svc, nil, err
+				}
+
+				n := len(r.Names)
+				if n == 0 {
+					n = 1
+				}
+				for i := 0; i < n; i++ {
+					m.results = append(m.results, s)
+				}
+			}
+		}
+
+		if len(m.results) == 0 || m.results[len(m.results)-1] != "error" {
+			return svc, nil, fmt.Errorf("%s.%s: the last result must be an error", name, m.name)
+		}
+
+		svc.methods = append(svc.methods, m)
+	}
+
+	return svc, used, nil
+}
+
+// qualify returns a copy of expr with the convoy package's exported types
+// prefixed with "convoy.", recording other packages it refers to.
+func qualify(expr ast.Expr, used *[]string) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if e.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent("convoy"), Sel: ast.NewIdent(e.Name)}
+		}
+		return e
+	case *ast.SelectorExpr:
+		if x, ok := e.X.(*ast.Ident); ok {
+			*used = append(*used, x.Name)
+		}
+		return e
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X, used)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt, used)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key, used), Value: qualify(e.Value, used)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: e.Dir, Value: qualify(e.Value, used)}
+	}
+
+	return expr
+}
+
+func writeFile(buf *bytes.Buffer, imports map[string]string, services []service) {
+	fmt.Fprintln(buf, "// Code generated by internal/mockgen. DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package convoymock")
+	fmt.Fprintln(buf)
+
+	names := make([]string, 0, len(imports))
+	for name := range imports {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := imports[names[i]], imports[names[j]]
+		if isStd(a) != isStd(b) {
+			return isStd(a)
+		}
+		return a < b
+	})
+
+	fmt.Fprintln(buf, "import (")
+	for i, name := range names {
+		path := imports[name]
+		if i > 0 && isStd(imports[names[i-1]]) && !isStd(path) {
+			fmt.Fprintln(buf)
+		}
+
+		if path[strings.LastIndex(path, "/")+1:] == name {
+			fmt.Fprintf(buf, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(buf, "\t%s %q\n", name, path)
+		}
+	}
+	fmt.Fprintln(buf, ")")
+
+	for _, svc := range services {
+		writeService(buf, svc)
+	}
+}
+
+// isStd reports whether path is a standard library package, which gofmt
+// groups first.
+func isStd(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+func writeService(buf *bytes.Buffer, svc service) {
+	fmt.Fprintf(buf, "\n// %s mocks convoy.%s.\n", svc.name, svc.name)
+	fmt.Fprintln(buf, "// Each method calls its Func field, or returns ErrNotMocked if it's nil.")
+	fmt.Fprintf(buf, "type %s struct {\n", svc.name)
+	for _, m := range svc.methods {
+		fmt.Fprintf(buf, "\t%sFunc func%s\n", m.name, m.signature())
+	}
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "\trecorder")
+	fmt.Fprintln(buf, "}")
+	fmt.Fprintf(buf, "\nvar _ convoy.%s = (*%s)(nil)\n", svc.name, svc.name)
+
+	for _, m := range svc.methods {
+		fmt.Fprintf(buf, "\nfunc (m *%s) %s%s {\n", svc.name, m.name, m.signature())
+		fmt.Fprintf(buf, "\tm.record(%q%s)\n", m.name, m.args(false, true))
+		fmt.Fprintf(buf, "\tif m.%sFunc == nil {\n", m.name)
+
+		var zeros []string
+		for i, r := range m.results[:len(m.results)-1] {
+			fmt.Fprintf(buf, "\t\tvar r%d %s\n", i, r)
+			zeros = append(zeros, fmt.Sprintf("r%d", i))
+		}
+		zeros = append(zeros, fmt.Sprintf("notMocked(%q, %q)", svc.name, m.name))
+
+		fmt.Fprintf(buf, "\t\treturn %s\n", strings.Join(zeros, ", "))
+		fmt.Fprintln(buf, "\t}")
+		fmt.Fprintf(buf, "\treturn m.%sFunc(%s)\n", m.name, m.args(true, false))
+		fmt.Fprintln(buf, "}")
+	}
+}
+
+func (m method) signature() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		if p.variadic {
+			params[i] = p.name + " ..." + p.typ
+		} else {
+			params[i] = p.name + " " + p.typ
+		}
+	}
+
+	results := strings.Join(m.results, ", ")
+	if len(m.results) > 1 {
+		results = "(" + results + ")"
+	}
+
+	return "(" + strings.Join(params, ", ") + ") " + results
+}
+
+// args lists the parameter names, spreading a variadic one when spread is
+// set, with a leading comma when lead is set.
+func (m method) args(spread, lead bool) string {
+	names := make([]string, len(m.params))
+	for i, p := range m.params {
+		names[i] = p.name
+		if p.variadic && spread {
+			names[i] += "..."
+		}
+	}
+
+	s := strings.Join(names, ", ")
+	if lead && s != "" {
+		s = ", " + s
+	}
+
+	return s
+}
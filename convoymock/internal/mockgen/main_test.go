@@ -0,0 +1,18 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMocksAreUpToDate(t *testing.T) {
+	want, err := generate("../../..")
+	require.NoError(t, err)
+
+	got, err := os.ReadFile("../../mocks.gen.go")
+	require.NoError(t, err)
+
+	require.Equal(t, string(want), string(got), "mocks are stale; run go generate ./convoymock")
+}
@@ -0,0 +1,618 @@
+// Code generated by internal/mockgen. DO NOT EDIT.
+
+package convoymock
+
+import (
+	"context"
+	"encoding/json"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+)
+
+// BrokerService mocks convoy.BrokerService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type BrokerService struct {
+	WriteEventFunc          func(ctx context.Context, body *convoy.CreateEventRequest) error
+	WriteFanoutEventFunc    func(ctx context.Context, body *convoy.CreateFanoutEventRequest) error
+	WriteBroadcastEventFunc func(ctx context.Context, body *convoy.CreateBroadcastEventRequest) error
+
+	recorder
+}
+
+var _ convoy.BrokerService = (*BrokerService)(nil)
+
+func (m *BrokerService) WriteEvent(ctx context.Context, body *convoy.CreateEventRequest) error {
+	m.record("WriteEvent", ctx, body)
+	if m.WriteEventFunc == nil {
+		return notMocked("BrokerService", "WriteEvent")
+	}
+	return m.WriteEventFunc(ctx, body)
+}
+
+func (m *BrokerService) WriteFanoutEvent(ctx context.Context, body *convoy.CreateFanoutEventRequest) error {
+	m.record("WriteFanoutEvent", ctx, body)
+	if m.WriteFanoutEventFunc == nil {
+		return notMocked("BrokerService", "WriteFanoutEvent")
+	}
+	return m.WriteFanoutEventFunc(ctx, body)
+}
+
+func (m *BrokerService) WriteBroadcastEvent(ctx context.Context, body *convoy.CreateBroadcastEventRequest) error {
+	m.record("WriteBroadcastEvent", ctx, body)
+	if m.WriteBroadcastEventFunc == nil {
+		return notMocked("BrokerService", "WriteBroadcastEvent")
+	}
+	return m.WriteBroadcastEventFunc(ctx, body)
+}
+
+// DeliveryAttemptService mocks convoy.DeliveryAttemptService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type DeliveryAttemptService struct {
+	AllFunc  func(ctx context.Context, eventDeliveryID string, query *convoy.DeliveryAttemptQueryParam) (*convoy.ListDeliveryAttemptResponse, error)
+	FindFunc func(ctx context.Context, eventDeliveryID string, deliveryAttemptID string, query *convoy.DeliveryAttemptQueryParam) (*convoy.DeliveryAttemptResponse, error)
+
+	recorder
+}
+
+var _ convoy.DeliveryAttemptService = (*DeliveryAttemptService)(nil)
+
+func (m *DeliveryAttemptService) All(ctx context.Context, eventDeliveryID string, query *convoy.DeliveryAttemptQueryParam) (*convoy.ListDeliveryAttemptResponse, error) {
+	m.record("All", ctx, eventDeliveryID, query)
+	if m.AllFunc == nil {
+		var r0 *convoy.ListDeliveryAttemptResponse
+		return r0, notMocked("DeliveryAttemptService", "All")
+	}
+	return m.AllFunc(ctx, eventDeliveryID, query)
+}
+
+func (m *DeliveryAttemptService) Find(ctx context.Context, eventDeliveryID string, deliveryAttemptID string, query *convoy.DeliveryAttemptQueryParam) (*convoy.DeliveryAttemptResponse, error) {
+	m.record("Find", ctx, eventDeliveryID, deliveryAttemptID, query)
+	if m.FindFunc == nil {
+		var r0 *convoy.DeliveryAttemptResponse
+		return r0, notMocked("DeliveryAttemptService", "Find")
+	}
+	return m.FindFunc(ctx, eventDeliveryID, deliveryAttemptID, query)
+}
+
+// EndpointService mocks convoy.EndpointService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type EndpointService struct {
+	AllFunc                  func(ctx context.Context, query *convoy.EndpointParams) (*convoy.ListEndpointResponse, error)
+	CreateFunc               func(ctx context.Context, body *convoy.CreateEndpointRequest, query *convoy.EndpointParams) (*convoy.EndpointResponse, error)
+	FindFunc                 func(ctx context.Context, endpointID string, query *convoy.EndpointParams) (*convoy.EndpointResponse, error)
+	UpdateFunc               func(ctx context.Context, endpointID string, body *convoy.CreateEndpointRequest, query *convoy.EndpointParams) (*convoy.EndpointResponse, error)
+	DeleteFunc               func(ctx context.Context, endpointID string, query *convoy.EndpointParams) error
+	PauseFunc                func(ctx context.Context, Id string) (*convoy.EndpointResponse, error)
+	ActivateFunc             func(ctx context.Context, Id string) (*convoy.EndpointResponse, error)
+	HealthFunc               func(ctx context.Context, Id string) (*convoy.EndpointHealth, error)
+	RollSecretFunc           func(ctx context.Context, Id string, body *convoy.RollSecretRequest) error
+	ExpireSecretFunc         func(ctx context.Context, Id string, body *convoy.RollSecretRequest) (*convoy.EndpointResponse, error)
+	TestOAuth2ConnectionFunc func(ctx context.Context, body *convoy.OAuth2Auth) (*convoy.OAuth2TestResponse, error)
+
+	recorder
+}
+
+var _ convoy.EndpointService = (*EndpointService)(nil)
+
+func (m *EndpointService) All(ctx context.Context, query *convoy.EndpointParams) (*convoy.ListEndpointResponse, error) {
+	m.record("All", ctx, query)
+	if m.AllFunc == nil {
+		var r0 *convoy.ListEndpointResponse
+		return r0, notMocked("EndpointService", "All")
+	}
+	return m.AllFunc(ctx, query)
+}
+
+func (m *EndpointService) Create(ctx context.Context, body *convoy.CreateEndpointRequest, query *convoy.EndpointParams) (*convoy.EndpointResponse, error) {
+	m.record("Create", ctx, body, query)
+	if m.CreateFunc == nil {
+		var r0 *convoy.EndpointResponse
+		return r0, notMocked("EndpointService", "Create")
+	}
+	return m.CreateFunc(ctx, body, query)
+}
+
+func (m *EndpointService) Find(ctx context.Context, endpointID string, query *convoy.EndpointParams) (*convoy.EndpointResponse, error) {
+	m.record("Find", ctx, endpointID, query)
+	if m.FindFunc == nil {
+		var r0 *convoy.EndpointResponse
+		return r0, notMocked("EndpointService", "Find")
+	}
+	return m.FindFunc(ctx, endpointID, query)
+}
+
+func (m *EndpointService) Update(ctx context.Context, endpointID string, body *convoy.CreateEndpointRequest, query *convoy.EndpointParams) (*convoy.EndpointResponse, error) {
+	m.record("Update", ctx, endpointID, body, query)
+	if m.UpdateFunc == nil {
+		var r0 *convoy.EndpointResponse
+		return r0, notMocked("EndpointService", "Update")
+	}
+	return m.UpdateFunc(ctx, endpointID, body, query)
+}
+
+func (m *EndpointService) Delete(ctx context.Context, endpointID string, query *convoy.EndpointParams) error {
+	m.record("Delete", ctx, endpointID, query)
+	if m.DeleteFunc == nil {
+		return notMocked("EndpointService", "Delete")
+	}
+	return m.DeleteFunc(ctx, endpointID, query)
+}
+
+func (m *EndpointService) Pause(ctx context.Context, Id string) (*convoy.EndpointResponse, error) {
+	m.record("Pause", ctx, Id)
+	if m.PauseFunc == nil {
+		var r0 *convoy.EndpointResponse
+		return r0, notMocked("EndpointService", "Pause")
+	}
+	return m.PauseFunc(ctx, Id)
+}
+
+func (m *EndpointService) Activate(ctx context.Context, Id string) (*convoy.EndpointResponse, error) {
+	m.record("Activate", ctx, Id)
+	if m.ActivateFunc == nil {
+		var r0 *convoy.EndpointResponse
+		return r0, notMocked("EndpointService", "Activate")
+	}
+	return m.ActivateFunc(ctx, Id)
+}
+
+func (m *EndpointService) Health(ctx context.Context, Id string) (*convoy.EndpointHealth, error) {
+	m.record("Health", ctx, Id)
+	if m.HealthFunc == nil {
+		var r0 *convoy.EndpointHealth
+		return r0, notMocked("EndpointService", "Health")
+	}
+	return m.HealthFunc(ctx, Id)
+}
+
+func (m *EndpointService) RollSecret(ctx context.Context, Id string, body *convoy.RollSecretRequest) error {
+	m.record("RollSecret", ctx, Id, body)
+	if m.RollSecretFunc == nil {
+		return notMocked("EndpointService", "RollSecret")
+	}
+	return m.RollSecretFunc(ctx, Id, body)
+}
+
+func (m *EndpointService) ExpireSecret(ctx context.Context, Id string, body *convoy.RollSecretRequest) (*convoy.EndpointResponse, error) {
+	m.record("ExpireSecret", ctx, Id, body)
+	if m.ExpireSecretFunc == nil {
+		var r0 *convoy.EndpointResponse
+		return r0, notMocked("EndpointService", "ExpireSecret")
+	}
+	return m.ExpireSecretFunc(ctx, Id, body)
+}
+
+func (m *EndpointService) TestOAuth2Connection(ctx context.Context, body *convoy.OAuth2Auth) (*convoy.OAuth2TestResponse, error) {
+	m.record("TestOAuth2Connection", ctx, body)
+	if m.TestOAuth2ConnectionFunc == nil {
+		var r0 *convoy.OAuth2TestResponse
+		return r0, notMocked("EndpointService", "TestOAuth2Connection")
+	}
+	return m.TestOAuth2ConnectionFunc(ctx, body)
+}
+
+// EventDeliveryService mocks convoy.EventDeliveryService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type EventDeliveryService struct {
+	AllFunc           func(ctx context.Context, query *convoy.EventDeliveryParams) (*convoy.ListEventDeliveryResponse, error)
+	FindFunc          func(ctx context.Context, eventDeliveryID string, query *convoy.EventDeliveryParams) (*convoy.EventDeliveryResponse, error)
+	ResendFunc        func(ctx context.Context, eventDeliveryID string, query *convoy.EventDeliveryParams) (*convoy.EventDeliveryResponse, error)
+	BatchResendFunc   func(ctx context.Context, query *convoy.EventDeliveryParams, opts ...convoy.BatchOption) error
+	ForceResendFunc   func(ctx context.Context, ids []string, opts ...convoy.ForceResendOption) (*convoy.ForceResendResult, error)
+	CountAffectedFunc func(ctx context.Context, query *convoy.EventDeliveryParams) (int, error)
+
+	recorder
+}
+
+var _ convoy.EventDeliveryService = (*EventDeliveryService)(nil)
+
+func (m *EventDeliveryService) All(ctx context.Context, query *convoy.EventDeliveryParams) (*convoy.ListEventDeliveryResponse, error) {
+	m.record("All", ctx, query)
+	if m.AllFunc == nil {
+		var r0 *convoy.ListEventDeliveryResponse
+		return r0, notMocked("EventDeliveryService", "All")
+	}
+	return m.AllFunc(ctx, query)
+}
+
+func (m *EventDeliveryService) Find(ctx context.Context, eventDeliveryID string, query *convoy.EventDeliveryParams) (*convoy.EventDeliveryResponse, error) {
+	m.record("Find", ctx, eventDeliveryID, query)
+	if m.FindFunc == nil {
+		var r0 *convoy.EventDeliveryResponse
+		return r0, notMocked("EventDeliveryService", "Find")
+	}
+	return m.FindFunc(ctx, eventDeliveryID, query)
+}
+
+func (m *EventDeliveryService) Resend(ctx context.Context, eventDeliveryID string, query *convoy.EventDeliveryParams) (*convoy.EventDeliveryResponse, error) {
+	m.record("Resend", ctx, eventDeliveryID, query)
+	if m.ResendFunc == nil {
+		var r0 *convoy.EventDeliveryResponse
+		return r0, notMocked("EventDeliveryService", "Resend")
+	}
+	return m.ResendFunc(ctx, eventDeliveryID, query)
+}
+
+func (m *EventDeliveryService) BatchResend(ctx context.Context, query *convoy.EventDeliveryParams, opts ...convoy.BatchOption) error {
+	m.record("BatchResend", ctx, query, opts)
+	if m.BatchResendFunc == nil {
+		return notMocked("EventDeliveryService", "BatchResend")
+	}
+	return m.BatchResendFunc(ctx, query, opts...)
+}
+
+func (m *EventDeliveryService) ForceResend(ctx context.Context, ids []string, opts ...convoy.ForceResendOption) (*convoy.ForceResendResult, error) {
+	m.record("ForceResend", ctx, ids, opts)
+	if m.ForceResendFunc == nil {
+		var r0 *convoy.ForceResendResult
+		return r0, notMocked("EventDeliveryService", "ForceResend")
+	}
+	return m.ForceResendFunc(ctx, ids, opts...)
+}
+
+func (m *EventDeliveryService) CountAffected(ctx context.Context, query *convoy.EventDeliveryParams) (int, error) {
+	m.record("CountAffected", ctx, query)
+	if m.CountAffectedFunc == nil {
+		var r0 int
+		return r0, notMocked("EventDeliveryService", "CountAffected")
+	}
+	return m.CountAffectedFunc(ctx, query)
+}
+
+// EventService mocks convoy.EventService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type EventService struct {
+	AllFunc            func(ctx context.Context, query *convoy.EventParams) (*convoy.ListEventResponse, error)
+	CreateFunc         func(ctx context.Context, body *convoy.CreateEventRequest) (*convoy.EventResponse, error)
+	FanoutEventFunc    func(ctx context.Context, body *convoy.CreateFanoutEventRequest) (*convoy.EventResponse, error)
+	BroadcastEventFunc func(ctx context.Context, body *convoy.CreateBroadcastEventRequest) (*convoy.BroadcastEventResponse, error)
+	CreateDynamicFunc  func(ctx context.Context, body *convoy.CreateDynamicEventRequest) error
+	FindFunc           func(ctx context.Context, eventID string) (*convoy.EventResponse, error)
+	ReplayFunc         func(ctx context.Context, eventID string) error
+	BatchReplayFunc    func(ctx context.Context, query *convoy.BatchReplayOptions, opts ...convoy.BatchOption) error
+	CountAffectedFunc  func(ctx context.Context, query *convoy.BatchReplayOptions) (int, error)
+
+	recorder
+}
+
+var _ convoy.EventService = (*EventService)(nil)
+
+func (m *EventService) All(ctx context.Context, query *convoy.EventParams) (*convoy.ListEventResponse, error) {
+	m.record("All", ctx, query)
+	if m.AllFunc == nil {
+		var r0 *convoy.ListEventResponse
+		return r0, notMocked("EventService", "All")
+	}
+	return m.AllFunc(ctx, query)
+}
+
+func (m *EventService) Create(ctx context.Context, body *convoy.CreateEventRequest) (*convoy.EventResponse, error) {
+	m.record("Create", ctx, body)
+	if m.CreateFunc == nil {
+		var r0 *convoy.EventResponse
+		return r0, notMocked("EventService", "Create")
+	}
+	return m.CreateFunc(ctx, body)
+}
+
+func (m *EventService) FanoutEvent(ctx context.Context, body *convoy.CreateFanoutEventRequest) (*convoy.EventResponse, error) {
+	m.record("FanoutEvent", ctx, body)
+	if m.FanoutEventFunc == nil {
+		var r0 *convoy.EventResponse
+		return r0, notMocked("EventService", "FanoutEvent")
+	}
+	return m.FanoutEventFunc(ctx, body)
+}
+
+func (m *EventService) BroadcastEvent(ctx context.Context, body *convoy.CreateBroadcastEventRequest) (*convoy.BroadcastEventResponse, error) {
+	m.record("BroadcastEvent", ctx, body)
+	if m.BroadcastEventFunc == nil {
+		var r0 *convoy.BroadcastEventResponse
+		return r0, notMocked("EventService", "BroadcastEvent")
+	}
+	return m.BroadcastEventFunc(ctx, body)
+}
+
+func (m *EventService) CreateDynamic(ctx context.Context, body *convoy.CreateDynamicEventRequest) error {
+	m.record("CreateDynamic", ctx, body)
+	if m.CreateDynamicFunc == nil {
+		return notMocked("EventService", "CreateDynamic")
+	}
+	return m.CreateDynamicFunc(ctx, body)
+}
+
+func (m *EventService) Find(ctx context.Context, eventID string) (*convoy.EventResponse, error) {
+	m.record("Find", ctx, eventID)
+	if m.FindFunc == nil {
+		var r0 *convoy.EventResponse
+		return r0, notMocked("EventService", "Find")
+	}
+	return m.FindFunc(ctx, eventID)
+}
+
+func (m *EventService) Replay(ctx context.Context, eventID string) error {
+	m.record("Replay", ctx, eventID)
+	if m.ReplayFunc == nil {
+		return notMocked("EventService", "Replay")
+	}
+	return m.ReplayFunc(ctx, eventID)
+}
+
+func (m *EventService) BatchReplay(ctx context.Context, query *convoy.BatchReplayOptions, opts ...convoy.BatchOption) error {
+	m.record("BatchReplay", ctx, query, opts)
+	if m.BatchReplayFunc == nil {
+		return notMocked("EventService", "BatchReplay")
+	}
+	return m.BatchReplayFunc(ctx, query, opts...)
+}
+
+func (m *EventService) CountAffected(ctx context.Context, query *convoy.BatchReplayOptions) (int, error) {
+	m.record("CountAffected", ctx, query)
+	if m.CountAffectedFunc == nil {
+		var r0 int
+		return r0, notMocked("EventService", "CountAffected")
+	}
+	return m.CountAffectedFunc(ctx, query)
+}
+
+// PortalLinkService mocks convoy.PortalLinkService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type PortalLinkService struct {
+	AllFunc          func(ctx context.Context) (*convoy.ListPortalLinkResponse, error)
+	CreateFunc       func(ctx context.Context, body *convoy.CreatePortalLinkRequest) (*convoy.PortalLinkResponse, error)
+	FindFunc         func(ctx context.Context, portalLinkID string) (*convoy.PortalLinkResponse, error)
+	UpdateFunc       func(ctx context.Context, portalLinkID string, body *convoy.UpdatePortalLinkRequest) (*convoy.PortalLinkResponse, error)
+	RevokeFunc       func(ctx context.Context, portalLinkID string) error
+	RefreshTokenFunc func(ctx context.Context, portalLinkID string) (string, error)
+	EmbedURLFunc     func(ctx context.Context, portalLinkID string) (string, error)
+
+	recorder
+}
+
+var _ convoy.PortalLinkService = (*PortalLinkService)(nil)
+
+func (m *PortalLinkService) All(ctx context.Context) (*convoy.ListPortalLinkResponse, error) {
+	m.record("All", ctx)
+	if m.AllFunc == nil {
+		var r0 *convoy.ListPortalLinkResponse
+		return r0, notMocked("PortalLinkService", "All")
+	}
+	return m.AllFunc(ctx)
+}
+
+func (m *PortalLinkService) Create(ctx context.Context, body *convoy.CreatePortalLinkRequest) (*convoy.PortalLinkResponse, error) {
+	m.record("Create", ctx, body)
+	if m.CreateFunc == nil {
+		var r0 *convoy.PortalLinkResponse
+		return r0, notMocked("PortalLinkService", "Create")
+	}
+	return m.CreateFunc(ctx, body)
+}
+
+func (m *PortalLinkService) Find(ctx context.Context, portalLinkID string) (*convoy.PortalLinkResponse, error) {
+	m.record("Find", ctx, portalLinkID)
+	if m.FindFunc == nil {
+		var r0 *convoy.PortalLinkResponse
+		return r0, notMocked("PortalLinkService", "Find")
+	}
+	return m.FindFunc(ctx, portalLinkID)
+}
+
+func (m *PortalLinkService) Update(ctx context.Context, portalLinkID string, body *convoy.UpdatePortalLinkRequest) (*convoy.PortalLinkResponse, error) {
+	m.record("Update", ctx, portalLinkID, body)
+	if m.UpdateFunc == nil {
+		var r0 *convoy.PortalLinkResponse
+		return r0, notMocked("PortalLinkService", "Update")
+	}
+	return m.UpdateFunc(ctx, portalLinkID, body)
+}
+
+func (m *PortalLinkService) Revoke(ctx context.Context, portalLinkID string) error {
+	m.record("Revoke", ctx, portalLinkID)
+	if m.RevokeFunc == nil {
+		return notMocked("PortalLinkService", "Revoke")
+	}
+	return m.RevokeFunc(ctx, portalLinkID)
+}
+
+func (m *PortalLinkService) RefreshToken(ctx context.Context, portalLinkID string) (string, error) {
+	m.record("RefreshToken", ctx, portalLinkID)
+	if m.RefreshTokenFunc == nil {
+		var r0 string
+		return r0, notMocked("PortalLinkService", "RefreshToken")
+	}
+	return m.RefreshTokenFunc(ctx, portalLinkID)
+}
+
+func (m *PortalLinkService) EmbedURL(ctx context.Context, portalLinkID string) (string, error) {
+	m.record("EmbedURL", ctx, portalLinkID)
+	if m.EmbedURLFunc == nil {
+		var r0 string
+		return r0, notMocked("PortalLinkService", "EmbedURL")
+	}
+	return m.EmbedURLFunc(ctx, portalLinkID)
+}
+
+// ProjectService mocks convoy.ProjectService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type ProjectService struct {
+	FindFunc   func(ctx context.Context, projectID string) (*convoy.ProjectResponse, error)
+	UpdateFunc func(ctx context.Context, projectID string, body *convoy.CreateProjectRequest) (*convoy.ProjectResponse, error)
+	DeleteFunc func(ctx context.Context, projectID string) error
+
+	recorder
+}
+
+var _ convoy.ProjectService = (*ProjectService)(nil)
+
+func (m *ProjectService) Find(ctx context.Context, projectID string) (*convoy.ProjectResponse, error) {
+	m.record("Find", ctx, projectID)
+	if m.FindFunc == nil {
+		var r0 *convoy.ProjectResponse
+		return r0, notMocked("ProjectService", "Find")
+	}
+	return m.FindFunc(ctx, projectID)
+}
+
+func (m *ProjectService) Update(ctx context.Context, projectID string, body *convoy.CreateProjectRequest) (*convoy.ProjectResponse, error) {
+	m.record("Update", ctx, projectID, body)
+	if m.UpdateFunc == nil {
+		var r0 *convoy.ProjectResponse
+		return r0, notMocked("ProjectService", "Update")
+	}
+	return m.UpdateFunc(ctx, projectID, body)
+}
+
+func (m *ProjectService) Delete(ctx context.Context, projectID string) error {
+	m.record("Delete", ctx, projectID)
+	if m.DeleteFunc == nil {
+		return notMocked("ProjectService", "Delete")
+	}
+	return m.DeleteFunc(ctx, projectID)
+}
+
+// SourceService mocks convoy.SourceService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type SourceService struct {
+	AllFunc          func(ctx context.Context, query *convoy.SourceParams) (*convoy.ListSourceResponse, error)
+	CreateFunc       func(ctx context.Context, body *convoy.CreateSourceRequest) (*convoy.SourceResponse, error)
+	FindFunc         func(ctx context.Context, sourceId string) (*convoy.SourceResponse, error)
+	UpdateFunc       func(ctx context.Context, sourceId string, body *convoy.CreateSourceRequest) (*convoy.SourceResponse, error)
+	DeleteFunc       func(ctx context.Context, sourceId string) error
+	TestFunctionFunc func(ctx context.Context, fn *convoy.SourceFunction, samplePayload json.RawMessage) (*convoy.FunctionResponse, error)
+
+	recorder
+}
+
+var _ convoy.SourceService = (*SourceService)(nil)
+
+func (m *SourceService) All(ctx context.Context, query *convoy.SourceParams) (*convoy.ListSourceResponse, error) {
+	m.record("All", ctx, query)
+	if m.AllFunc == nil {
+		var r0 *convoy.ListSourceResponse
+		return r0, notMocked("SourceService", "All")
+	}
+	return m.AllFunc(ctx, query)
+}
+
+func (m *SourceService) Create(ctx context.Context, body *convoy.CreateSourceRequest) (*convoy.SourceResponse, error) {
+	m.record("Create", ctx, body)
+	if m.CreateFunc == nil {
+		var r0 *convoy.SourceResponse
+		return r0, notMocked("SourceService", "Create")
+	}
+	return m.CreateFunc(ctx, body)
+}
+
+func (m *SourceService) Find(ctx context.Context, sourceId string) (*convoy.SourceResponse, error) {
+	m.record("Find", ctx, sourceId)
+	if m.FindFunc == nil {
+		var r0 *convoy.SourceResponse
+		return r0, notMocked("SourceService", "Find")
+	}
+	return m.FindFunc(ctx, sourceId)
+}
+
+func (m *SourceService) Update(ctx context.Context, sourceId string, body *convoy.CreateSourceRequest) (*convoy.SourceResponse, error) {
+	m.record("Update", ctx, sourceId, body)
+	if m.UpdateFunc == nil {
+		var r0 *convoy.SourceResponse
+		return r0, notMocked("SourceService", "Update")
+	}
+	return m.UpdateFunc(ctx, sourceId, body)
+}
+
+func (m *SourceService) Delete(ctx context.Context, sourceId string) error {
+	m.record("Delete", ctx, sourceId)
+	if m.DeleteFunc == nil {
+		return notMocked("SourceService", "Delete")
+	}
+	return m.DeleteFunc(ctx, sourceId)
+}
+
+func (m *SourceService) TestFunction(ctx context.Context, fn *convoy.SourceFunction, samplePayload json.RawMessage) (*convoy.FunctionResponse, error) {
+	m.record("TestFunction", ctx, fn, samplePayload)
+	if m.TestFunctionFunc == nil {
+		var r0 *convoy.FunctionResponse
+		return r0, notMocked("SourceService", "TestFunction")
+	}
+	return m.TestFunctionFunc(ctx, fn, samplePayload)
+}
+
+// SubscriptionService mocks convoy.SubscriptionService.
+// Each method calls its Func field, or returns ErrNotMocked if it's nil.
+type SubscriptionService struct {
+	AllFunc          func(ctx context.Context, query *convoy.SubscriptionParams) (*convoy.ListSubscriptionResponse, error)
+	CreateFunc       func(ctx context.Context, body *convoy.CreateSubscriptionRequest) (*convoy.SubscriptionResponse, error)
+	FindFunc         func(ctx context.Context, subscriptionId string) (*convoy.SubscriptionResponse, error)
+	UpdateFunc       func(ctx context.Context, subscriptionId string, body *convoy.CreateSubscriptionRequest) (*convoy.SubscriptionResponse, error)
+	DeleteFunc       func(ctx context.Context, subscriptionId string) error
+	ToggleFunc       func(ctx context.Context, subscriptionId string) (*convoy.SubscriptionResponse, error)
+	TestFunctionFunc func(ctx context.Context, body *convoy.FunctionRequest) (*convoy.FunctionResponse, error)
+
+	recorder
+}
+
+var _ convoy.SubscriptionService = (*SubscriptionService)(nil)
+
+func (m *SubscriptionService) All(ctx context.Context, query *convoy.SubscriptionParams) (*convoy.ListSubscriptionResponse, error) {
+	m.record("All", ctx, query)
+	if m.AllFunc == nil {
+		var r0 *convoy.ListSubscriptionResponse
+		return r0, notMocked("SubscriptionService", "All")
+	}
+	return m.AllFunc(ctx, query)
+}
+
+func (m *SubscriptionService) Create(ctx context.Context, body *convoy.CreateSubscriptionRequest) (*convoy.SubscriptionResponse, error) {
+	m.record("Create", ctx, body)
+	if m.CreateFunc == nil {
+		var r0 *convoy.SubscriptionResponse
+		return r0, notMocked("SubscriptionService", "Create")
+	}
+	return m.CreateFunc(ctx, body)
+}
+
+func (m *SubscriptionService) Find(ctx context.Context, subscriptionId string) (*convoy.SubscriptionResponse, error) {
+	m.record("Find", ctx, subscriptionId)
+	if m.FindFunc == nil {
+		var r0 *convoy.SubscriptionResponse
+		return r0, notMocked("SubscriptionService", "Find")
+	}
+	return m.FindFunc(ctx, subscriptionId)
+}
+
+func (m *SubscriptionService) Update(ctx context.Context, subscriptionId string, body *convoy.CreateSubscriptionRequest) (*convoy.SubscriptionResponse, error) {
+	m.record("Update", ctx, subscriptionId, body)
+	if m.UpdateFunc == nil {
+		var r0 *convoy.SubscriptionResponse
+		return r0, notMocked("SubscriptionService", "Update")
+	}
+	return m.UpdateFunc(ctx, subscriptionId, body)
+}
+
+func (m *SubscriptionService) Delete(ctx context.Context, subscriptionId string) error {
+	m.record("Delete", ctx, subscriptionId)
+	if m.DeleteFunc == nil {
+		return notMocked("SubscriptionService", "Delete")
+	}
+	return m.DeleteFunc(ctx, subscriptionId)
+}
+
+func (m *SubscriptionService) Toggle(ctx context.Context, subscriptionId string) (*convoy.SubscriptionResponse, error) {
+	m.record("Toggle", ctx, subscriptionId)
+	if m.ToggleFunc == nil {
+		var r0 *convoy.SubscriptionResponse
+		return r0, notMocked("SubscriptionService", "Toggle")
+	}
+	return m.ToggleFunc(ctx, subscriptionId)
+}
+
+func (m *SubscriptionService) TestFunction(ctx context.Context, body *convoy.FunctionRequest) (*convoy.FunctionResponse, error) {
+	m.record("TestFunction", ctx, body)
+	if m.TestFunctionFunc == nil {
+		var r0 *convoy.FunctionResponse
+		return r0, notMocked("SubscriptionService", "TestFunction")
+	}
+	return m.TestFunctionFunc(ctx, body)
+}
@@ -0,0 +1,332 @@
+package convoytest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+)
+
+// More delivery statuses, used once OptionDelivery is set.
+const (
+	StatusRetry     = "Retry"
+	StatusDiscarded = "Discarded"
+)
+
+var (
+	// DefaultDeliveryTimeout bounds a delivery request when the endpoint
+	// sets no http_timeout.
+	DefaultDeliveryTimeout = 30 * time.Second
+	// DefaultResponseLimit caps the response body kept in an attempt.
+	DefaultResponseLimit int64 = 4 << 10
+)
+
+// DeliveryOptions configure the delivery engine.
+type DeliveryOptions struct {
+	// Client sends the deliveries. Defaults to http.DefaultClient.
+	Client *http.Client
+	// IntervalUnit is how long a second of a retry interval lasts, so
+	// retries can run faster than Convoy's; time.Millisecond turns a 10s
+	// interval into 10ms. Defaults to time.Second.
+	IntervalUnit time.Duration
+}
+
+// OptionDelivery makes the server deliver events: each delivery is signed
+// with the endpoint's secrets, POSTed to the endpoint URL and retried on
+// failure following the subscription's retry config, or the project's
+// strategy, until it has been tried retry-limit times. Every request is
+// recorded as a delivery attempt. Use Wait to block until deliveries are
+// done.
+func OptionDelivery(opts *DeliveryOptions) func(s *Server) {
+	return func(s *Server) {
+		o := DeliveryOptions{}
+		if opts != nil {
+			o = *opts
+		}
+
+		if o.Client == nil {
+			o.Client = http.DefaultClient
+		}
+
+		if o.IntervalUnit <= 0 {
+			o.IntervalUnit = time.Second
+		}
+
+		s.deliveryOpts = &o
+	}
+}
+
+// Wait blocks until no delivery is scheduled or waiting to be retried.
+func (s *Server) Wait() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.inflight > 0 {
+		s.idle.Wait()
+	}
+}
+
+// schedule starts sending a delivery if the delivery engine is on. It must
+// be called with s.mu held.
+func (s *Server) schedule(id string) {
+	if s.deliveryOpts == nil {
+		return
+	}
+
+	s.inflight++
+	go s.run(id)
+}
+
+// run sends a delivery until it succeeds or runs out of retries.
+func (s *Server) run(id string) {
+	defer func() {
+		s.mu.Lock()
+		s.inflight--
+		if s.inflight == 0 {
+			s.idle.Broadcast()
+		}
+		s.mu.Unlock()
+	}()
+
+	for {
+		if s.stop.Err() != nil {
+			return
+		}
+
+		s.mu.Lock()
+		req, ok := s.prepare(id)
+		s.mu.Unlock()
+		if !ok {
+			return
+		}
+
+		attempt := s.send(req)
+
+		s.mu.Lock()
+		wait, retry := s.record(id, attempt)
+		s.mu.Unlock()
+		if !retry {
+			return
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-s.stop.Done():
+			return
+		}
+	}
+}
+
+type deliveryRequest struct {
+	deliveryID string
+	endpointID string
+	url        string
+	header     http.Header
+	body       []byte
+	timeout    time.Duration
+}
+
+// prepare builds the request for a delivery, discarding deliveries whose
+// endpoint is gone or isn't active.
+func (s *Server) prepare(id string) (*deliveryRequest, bool) {
+	d, ok := s.deliveries.get(id)
+	if !ok || (d.Status != StatusScheduled && d.Status != StatusRetry) {
+		return nil, false
+	}
+
+	ep, ok := s.endpoints.get(d.EndpointID)
+	if !ok || ep.Status != convoy.EndpointStatusActive {
+		d.Status = StatusDiscarded
+		d.Description = "endpoint is not active"
+		d.UpdatedAt = s.now()
+		return nil, false
+	}
+
+	req := &deliveryRequest{
+		deliveryID: d.UID,
+		endpointID: ep.UID,
+		url:        ep.URL,
+		header:     http.Header{},
+		body:       d.Metadata.Data,
+		timeout:    DefaultDeliveryTimeout,
+	}
+
+	if ep.HttpTimeout > 0 {
+		req.timeout = time.Duration(ep.HttpTimeout) * time.Second
+	}
+
+	if e, ok := s.events.get(d.EventID); ok {
+		for k, v := range e.headers {
+			req.header[k] = v
+		}
+	}
+
+	req.header.Set("Content-Type", ep.ContentType)
+	req.header.Set("User-Agent", "Convoy/convoytest")
+	req.header.Set(s.signatureHeader(), s.sign(ep, req.body))
+
+	if auth := ep.Authentication; auth != nil {
+		switch {
+		case auth.ApiKey != nil:
+			req.header.Set(auth.ApiKey.HeaderName, auth.ApiKey.HeaderValue)
+		case auth.BasicAuth != nil:
+			creds := auth.BasicAuth.UserName + ":" + auth.BasicAuth.Password
+			req.header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(creds)))
+		}
+	}
+
+	return req, true
+}
+
+func (s *Server) signatureHeader() string {
+	if sig := s.project.Config.Signature; sig != nil && sig.Header != "" {
+		return sig.Header
+	}
+
+	return convoy.DefaultSigHeader
+}
+
+// sign computes the signature header the way Convoy does: a hex HMAC of
+// the body with the newest secret, or with advanced signatures, "t=<unix>"
+// followed by a "v1=" HMAC of "<unix>,<body>" for every unexpired secret.
+func (s *Server) sign(ep *convoy.EndpointResponse, body []byte) string {
+	fn := sha256.New
+	if sig := s.project.Config.Signature; sig != nil && strings.EqualFold(sig.Hash, "SHA512") {
+		fn = sha512.New
+	}
+
+	mac := func(secret string, parts ...[]byte) string {
+		h := hmac.New(fn, []byte(secret))
+		for _, p := range parts {
+			h.Write(p)
+		}
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	if len(ep.Secrets) == 0 {
+		return ""
+	}
+
+	if !ep.AdvancedSignatures {
+		return mac(ep.Secrets[len(ep.Secrets)-1].Value, body)
+	}
+
+	now := s.now()
+	ts := fmt.Sprintf("%d", now.Unix())
+
+	parts := []string{"t=" + ts}
+	for _, secret := range ep.Secrets {
+		if !secret.ExpiresAt.IsZero() && secret.ExpiresAt.Before(now) {
+			continue
+		}
+		parts = append(parts, "v1="+mac(secret.Value, []byte(ts), []byte(","), body))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// send makes the request without holding the server lock.
+func (s *Server) send(req *deliveryRequest) convoy.DeliveryAttemptResponse {
+	attempt := convoy.DeliveryAttemptResponse{
+		MsgID:         req.deliveryID,
+		URL:           req.url,
+		Method:        http.MethodPost,
+		EndpointID:    req.endpointID,
+		APIVersion:    "2025-11-24",
+		RequestHeader: flatten(req.header),
+	}
+
+	ctx, cancel := context.WithTimeout(s.stop, req.timeout)
+	defer cancel()
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, req.url, bytes.NewReader(req.body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	r.Header = req.header
+
+	resp, err := s.deliveryOpts.Client.Do(r)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, DefaultResponseLimit))
+
+	attempt.HttpResponseCode = resp.Status
+	attempt.ResponseHeader = flatten(resp.Header)
+	attempt.ResponseData = string(body)
+	attempt.Status = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !attempt.Status {
+		attempt.Error = fmt.Sprintf("endpoint returned %s", resp.Status)
+	}
+
+	return attempt
+}
+
+// record stores an attempt and updates its delivery, returning how long to
+// wait before the next try if there is one.
+func (s *Server) record(id string, attempt convoy.DeliveryAttemptResponse) (time.Duration, bool) {
+	d, ok := s.deliveries.get(id)
+	if !ok {
+		return 0, false
+	}
+
+	now := s.now()
+	attempt.UID = s.newID("att")
+	attempt.CreatedAt = now
+	attempt.UpdatedAt = now
+	s.attempts[id] = append(s.attempts[id], attempt)
+
+	d.Metadata.NumTrials++
+	d.UpdatedAt = now
+
+	if attempt.Status {
+		d.Status = StatusSuccess
+		d.Description = ""
+		return 0, false
+	}
+
+	d.Description = attempt.Error
+
+	atMostOnce := false
+	if sub, ok := s.subscriptions.get(d.SubscriptionID); ok {
+		atMostOnce = sub.DeliveryMode == convoy.DeliveryModeAtMostOnce
+	}
+
+	if atMostOnce || d.Metadata.NumTrials >= d.Metadata.RetryLimit {
+		d.Status = StatusFailure
+		return 0, false
+	}
+
+	interval := time.Duration(d.Metadata.IntervalSeconds)
+	if d.Metadata.Strategy == "exponential" {
+		interval <<= d.Metadata.NumTrials - 1
+	}
+
+	d.Status = StatusRetry
+	d.Metadata.NextSendTime = now.Add(interval * time.Second)
+
+	return interval * s.deliveryOpts.IntervalUnit, true
+}
+
+func flatten(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k := range h {
+		out[k] = h.Get(k)
+	}
+
+	return out
+}
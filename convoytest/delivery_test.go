@@ -0,0 +1,148 @@
+package convoytest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliverySignsAndRetries(t *testing.T) {
+	webhook := convoy.NewWebhook(&convoy.WebhookOpts{Secret: "endpoint-secret"})
+
+	var calls int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := webhook.VerifyRequest(r); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		if atomic.AddInt32(&calls, 1) < 3 {
+			http.Error(w, "try again", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	srv := NewServer(OptionDelivery(&DeliveryOptions{IntervalUnit: time.Millisecond}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := srv.Client()
+
+	ep, err := c.Endpoints.Create(ctx, &convoy.CreateEndpointRequest{
+		Name:   "receiver",
+		URL:    receiver.URL,
+		Secret: "endpoint-secret",
+	}, nil)
+	require.NoError(t, err)
+
+	_, err = c.Subscriptions.Create(ctx, &convoy.CreateSubscriptionRequest{
+		Name:        "all",
+		EndpointID:  ep.UID,
+		RetryConfig: &convoy.RetryConfiguration{Type: "linear", Duration: "5s", RetryCount: 3},
+	})
+	require.NoError(t, err)
+
+	_, err = c.Events.Create(ctx, &convoy.CreateEventRequest{EndpointID: ep.UID, EventType: "invoice.paid", Data: []byte(`{"amount": 1}`)})
+	require.NoError(t, err)
+
+	srv.Wait()
+
+	d := srv.Deliveries()[0]
+	require.Equal(t, StatusSuccess, d.Status)
+	require.Equal(t, uint64(3), d.Metadata.NumTrials)
+
+	attempts, err := c.DeliveryAttempts.All(ctx, d.UID, nil)
+	require.NoError(t, err)
+	require.Len(t, *attempts, 3)
+	require.False(t, (*attempts)[0].Status)
+	require.Equal(t, "503 Service Unavailable", (*attempts)[0].HttpResponseCode)
+	require.True(t, (*attempts)[2].Status)
+}
+
+func TestDeliveryFailsAfterRetryLimit(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "broken", http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	srv := NewServer(OptionDelivery(&DeliveryOptions{IntervalUnit: time.Millisecond}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := srv.Client()
+
+	ep, err := c.Endpoints.Create(ctx, &convoy.CreateEndpointRequest{Name: "receiver", URL: receiver.URL}, nil)
+	require.NoError(t, err)
+
+	_, err = c.Events.Create(ctx, &convoy.CreateEventRequest{EndpointID: ep.UID, EventType: "invoice.paid", Data: []byte(`{}`)})
+	require.NoError(t, err)
+
+	srv.Wait()
+
+	d := srv.Deliveries()[0]
+	require.Equal(t, StatusFailure, d.Status)
+	require.Equal(t, uint64(3), d.Metadata.NumTrials, "the project strategy allows three tries")
+
+	// A manual resend gets one more try.
+	_, err = c.EventDeliveries.Resend(ctx, d.UID, nil)
+	require.NoError(t, err)
+	srv.Wait()
+
+	attempts, err := c.DeliveryAttempts.All(ctx, d.UID, nil)
+	require.NoError(t, err)
+	require.Len(t, *attempts, 4)
+}
+
+func TestDeliveryUsesAdvancedSignaturesForRotatedSecrets(t *testing.T) {
+	oldSecret := convoy.NewWebhook(&convoy.WebhookOpts{Secret: "old-secret"})
+	newSecret := convoy.NewWebhook(&convoy.WebhookOpts{Secret: "new-secret"})
+
+	type delivery struct {
+		body   []byte
+		header string
+	}
+
+	received := make(chan delivery, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{body: body, header: r.Header.Get(convoy.DefaultSigHeader)}
+	}))
+	defer receiver.Close()
+
+	srv := NewServer(OptionDelivery(nil))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := srv.Client()
+
+	advanced := true
+	ep, err := c.Endpoints.Create(ctx, &convoy.CreateEndpointRequest{
+		Name:               "receiver",
+		URL:                receiver.URL,
+		Secret:             "old-secret",
+		AdvancedSignatures: &advanced,
+	}, nil)
+	require.NoError(t, err)
+
+	err = c.Endpoints.RollSecret(ctx, ep.UID, &convoy.RollSecretRequest{Expiration: 1, Secret: "new-secret"})
+	require.NoError(t, err)
+
+	_, err = c.Events.Create(ctx, &convoy.CreateEventRequest{EndpointID: ep.UID, EventType: "invoice.paid", Data: []byte(`{"amount": 1}`)})
+	require.NoError(t, err)
+
+	srv.Wait()
+
+	d := <-received
+	require.NoError(t, oldSecret.VerifyPayload(d.body, d.header))
+	require.NoError(t, newSecret.VerifyPayload(d.body, d.header))
+}
@@ -0,0 +1,564 @@
+package convoytest
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+	"github.com/frain-dev/convoy-go/v2/filter"
+)
+
+// Delivery statuses, as Convoy reports them.
+const (
+	StatusScheduled = "Scheduled"
+	StatusSuccess   = "Success"
+	StatusFailure   = "Failure"
+)
+
+// publish records an event sent to targets and creates a delivery for each
+// active subscription it matches. Targets without subscriptions get a
+// delivery of their own unless subscribedOnly is set.
+func (s *Server) publish(eventType string, data []byte, customHeaders map[string]string, key string, targets []*convoy.EndpointResponse, subscribedOnly bool) *event {
+	headers := http.Header{}
+	for k, v := range customHeaders {
+		headers.Set(k, v)
+	}
+
+	now := s.now()
+	e := &event{
+		EventResponse: convoy.EventResponse{
+			UID:            s.newID("evt"),
+			EventType:      eventType,
+			IdempotencyKey: key,
+			Status:         StatusSuccess,
+			Data:           data,
+			Endpoints:      []string{},
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		},
+		headers: headers,
+	}
+
+	if key != "" && s.seenIdempotencyKey(key) {
+		e.IsDuplicateEvent = true
+		s.events.put(e.UID, e)
+		return e
+	}
+
+	s.events.put(e.UID, e)
+	for _, ep := range targets {
+		delivered := s.deliver(e, ep, subscribedOnly)
+		if delivered {
+			e.MatchedEndpoints++
+		}
+
+		// Direct and fan-out events list every endpoint they were sent to,
+		// broadcasts only the ones they reached.
+		if delivered || !subscribedOnly {
+			e.Endpoints = append(e.Endpoints, ep.UID)
+		}
+	}
+
+	return e
+}
+
+func (s *Server) seenIdempotencyKey(key string) bool {
+	for _, e := range s.events.oldestFirst() {
+		if e.IdempotencyKey == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deliver creates the deliveries of e to ep and reports whether there were
+// any.
+func (s *Server) deliver(e *event, ep *convoy.EndpointResponse, subscribedOnly bool) bool {
+	var subs []*subscription
+	for _, sub := range s.subscriptions.oldestFirst() {
+		if sub.endpointID == ep.UID {
+			subs = append(subs, sub)
+		}
+	}
+
+	if len(subs) == 0 {
+		if subscribedOnly {
+			return false
+		}
+
+		s.newDelivery(e, ep, nil)
+		return true
+	}
+
+	matched := false
+	for _, sub := range subs {
+		if sub.Status != "active" {
+			continue
+		}
+
+		ok, err := filter.MatchConfig(sub.FilterConfig, e.EventType, e.Data, e.headers)
+		if err != nil || !ok {
+			continue
+		}
+
+		s.newDelivery(e, ep, sub)
+		matched = true
+	}
+
+	return matched
+}
+
+func (s *Server) newDelivery(e *event, ep *convoy.EndpointResponse, sub *subscription) *convoy.EventDeliveryResponse {
+	strategy, interval, limit := s.retryPolicy(sub)
+
+	now := s.now()
+	d := &convoy.EventDeliveryResponse{
+		UID:           s.newID("dlv"),
+		EventID:       e.UID,
+		EndpointID:    ep.UID,
+		EventMetadata: convoy.EventMetadata{UID: e.UID, Name: e.EventType},
+		Metadata: convoy.Metadata{
+			Data:            e.Data,
+			Strategy:        strategy,
+			NextSendTime:    now,
+			IntervalSeconds: interval,
+			RetryLimit:      limit,
+		},
+		Status:    StatusScheduled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if sub != nil {
+		d.SubscriptionID = sub.UID
+	}
+
+	s.deliveries.put(d.UID, d)
+	s.schedule(d.UID)
+
+	return d
+}
+
+// retryPolicy returns the subscription's retry config, falling back to the
+// project's strategy.
+func (s *Server) retryPolicy(sub *subscription) (strategy string, interval, limit uint64) {
+	if st := s.project.Config; st != nil && st.Strategy != nil {
+		strategy, interval, limit = st.Strategy.Type, st.Strategy.Duration, st.Strategy.RetryCount
+	}
+
+	if sub == nil || sub.RetryConfig == nil {
+		return strategy, interval, limit
+	}
+
+	rc := sub.RetryConfig
+	if rc.Type != "" {
+		strategy = rc.Type
+	}
+	if d, err := time.ParseDuration(rc.Duration); err == nil {
+		interval = uint64(d / time.Second)
+	}
+	if rc.RetryCount > 0 {
+		limit = uint64(rc.RetryCount)
+	}
+
+	return strategy, interval, limit
+}
+
+func validateEvent(eventType string, data []byte) string {
+	if strings.TrimSpace(eventType) == "" {
+		return "please provide an event type"
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "please provide your data"
+	}
+
+	return ""
+}
+
+func (s *Server) createEvent(w http.ResponseWriter, r *http.Request) {
+	body := &convoy.CreateEventRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := validateEvent(body.EventType, body.Data); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	ep, ok := s.endpoints.get(body.EndpointID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "endpoint not found")
+		return
+	}
+
+	e := s.publish(body.EventType, body.Data, body.CustomHeaders, body.IdempotencyKey, []*convoy.EndpointResponse{ep}, false)
+	writeJSON(w, http.StatusCreated, "Endpoint event created successfully", e.EventResponse)
+}
+
+func (s *Server) fanoutEvent(w http.ResponseWriter, r *http.Request) {
+	body := &convoy.CreateFanoutEventRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := validateEvent(body.EventType, body.Data); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if body.OwnerID == "" {
+		writeError(w, http.StatusBadRequest, "please provide an owner id")
+		return
+	}
+
+	var targets []*convoy.EndpointResponse
+	for _, ep := range s.endpoints.oldestFirst() {
+		if ep.OwnerID == body.OwnerID {
+			targets = append(targets, ep)
+		}
+	}
+
+	if len(targets) == 0 {
+		writeError(w, http.StatusNotFound, "no endpoints found for owner id")
+		return
+	}
+
+	e := s.publish(body.EventType, body.Data, body.CustomHeaders, body.IdempotencyKey, targets, false)
+	writeJSON(w, http.StatusCreated, "Endpoint event created successfully", e.EventResponse)
+}
+
+func (s *Server) broadcastEvent(w http.ResponseWriter, r *http.Request) {
+	body := &convoy.CreateBroadcastEventRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := validateEvent(body.EventType, body.Data); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	e := s.publish(body.EventType, body.Data, body.CustomHeaders, body.IdempotencyKey, s.endpoints.oldestFirst(), true)
+	writeJSON(w, http.StatusCreated, "Broadcast event created successfully", e.EventResponse)
+}
+
+// dynamicEvent finds the endpoint with the body's URL, creating it and a
+// subscription for the body's event types if needed.
+func (s *Server) dynamicEvent(w http.ResponseWriter, r *http.Request) {
+	body := &convoy.CreateDynamicEventRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := validateEvent(body.EventType, body.Data); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	u, err := url.Parse(body.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		writeError(w, http.StatusBadRequest, "please provide a valid url")
+		return
+	}
+
+	var ep *convoy.EndpointResponse
+	for _, existing := range s.endpoints.oldestFirst() {
+		if existing.URL == body.URL {
+			ep = existing
+			break
+		}
+	}
+
+	if ep == nil {
+		ep = s.addEndpoint(&convoy.CreateEndpointRequest{Name: body.URL, URL: body.URL, Secret: body.Secret})
+		if len(body.EventTypes) > 0 {
+			s.addSubscription(&convoy.CreateSubscriptionRequest{
+				Name:         ep.Name,
+				EndpointID:   ep.UID,
+				FilterConfig: &convoy.FilterConfiguration{EventTypes: body.EventTypes},
+			})
+		}
+	}
+
+	e := s.publish(body.EventType, body.Data, body.CustomHeaders, body.IdempotencyKey, []*convoy.EndpointResponse{ep}, false)
+	writeJSON(w, http.StatusCreated, "Dynamic event created successfully", e.EventResponse)
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	start, end, err := dateRange(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid date: "+err.Error())
+		return
+	}
+
+	endpointIDs, key, search := q["endpointId"], q.Get("idempotencyKey"), q.Get("query")
+
+	items := s.events.newestFirst(func(e *event) bool {
+		if key != "" && e.IdempotencyKey != key {
+			return false
+		}
+
+		if search != "" && !strings.Contains(string(e.Data), search) {
+			return false
+		}
+
+		if len(endpointIDs) > 0 && !sharesAny(e.Endpoints, endpointIDs) {
+			return false
+		}
+
+		// Events sent through the API have no source.
+		return q.Get("sourceId") == "" && inRange(e.CreatedAt, start, end)
+	})
+
+	page, p := paginate(r, items, func(e *event) string { return e.UID })
+
+	content := make([]convoy.EventResponse, len(page))
+	for i := range page {
+		content[i] = page[i].EventResponse
+	}
+
+	writeJSON(w, http.StatusOK, "App events fetched successfully", convoy.ListEventResponse{Content: content, Pagination: p})
+}
+
+func sharesAny(a, b []string) bool {
+	for _, v := range a {
+		if contains(b, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) event(w http.ResponseWriter, r *http.Request) (*event, bool) {
+	e, ok := s.events.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "event not found")
+	}
+
+	return e, ok
+}
+
+func (s *Server) getEvent(w http.ResponseWriter, r *http.Request) {
+	if e, ok := s.event(w, r); ok {
+		writeJSON(w, http.StatusOK, "App event fetched successfully", e.EventResponse)
+	}
+}
+
+func (s *Server) replayEvent(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.event(w, r)
+	if !ok {
+		return
+	}
+
+	s.replay(e)
+	writeJSON(w, http.StatusOK, "App event replayed successfully", e.EventResponse)
+}
+
+// replay creates new deliveries of e to the endpoints it went to.
+func (s *Server) replay(e *event) {
+	for _, id := range e.Endpoints {
+		if ep, ok := s.endpoints.get(id); ok {
+			s.deliver(e, ep, false)
+		}
+	}
+	e.UpdatedAt = s.now()
+}
+
+// replayable returns the events batchreplay and countbatchreplayevents act
+// on.
+func (s *Server) replayable(w http.ResponseWriter, r *http.Request) ([]*event, bool) {
+	q := r.URL.Query()
+
+	start, end, err := dateRange(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid date: "+err.Error())
+		return nil, false
+	}
+
+	return s.events.newestFirst(func(e *event) bool {
+		return q.Get("sourceId") == "" && !e.IsDuplicateEvent && inRange(e.CreatedAt, start, end)
+	}), true
+}
+
+func (s *Server) batchReplay(w http.ResponseWriter, r *http.Request) {
+	events, ok := s.replayable(w, r)
+	if !ok {
+		return
+	}
+
+	for _, e := range events {
+		s.replay(e)
+	}
+
+	writeJSON(w, http.StatusOK, fmt.Sprintf("%d successful, 0 failed", len(events)), nil)
+}
+
+func (s *Server) countBatchReplay(w http.ResponseWriter, r *http.Request) {
+	events, ok := s.replayable(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, "events count successful", map[string]int{"num": len(events)})
+}
+
+// deliveryFilter reads the event delivery list filters.
+func deliveryFilter(q url.Values) (func(d *convoy.EventDeliveryResponse) bool, error) {
+	start, end, err := dateRange(q)
+	if err != nil {
+		return nil, err
+	}
+
+	eventID, subscriptionID, eventType := q.Get("eventId"), q.Get("subscriptionId"), q.Get("eventType")
+	endpointIDs, statuses := q["endpointId"], q["status"]
+
+	return func(d *convoy.EventDeliveryResponse) bool {
+		return (eventID == "" || d.EventID == eventID) &&
+			(subscriptionID == "" || d.SubscriptionID == subscriptionID) &&
+			(eventType == "" || d.EventMetadata.Name == eventType) &&
+			anyOf(endpointIDs, d.EndpointID) &&
+			anyOf(statuses, d.Status) &&
+			inRange(d.CreatedAt, start, end)
+	}, nil
+}
+
+func (s *Server) listDeliveries(w http.ResponseWriter, r *http.Request) {
+	keep, err := deliveryFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid date: "+err.Error())
+		return
+	}
+
+	page, p := paginate(r, s.deliveries.newestFirst(keep), func(d *convoy.EventDeliveryResponse) string { return d.UID })
+	writeJSON(w, http.StatusOK, "Event deliveries fetched successfully", convoy.ListEventDeliveryResponse{Content: page, Pagination: p})
+}
+
+func (s *Server) delivery(w http.ResponseWriter, r *http.Request) (*convoy.EventDeliveryResponse, bool) {
+	d, ok := s.deliveries.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "event delivery not found")
+	}
+
+	return d, ok
+}
+
+func (s *Server) getDelivery(w http.ResponseWriter, r *http.Request) {
+	if d, ok := s.delivery(w, r); ok {
+		writeJSON(w, http.StatusOK, "Event Delivery fetched successfully", d)
+	}
+}
+
+// reschedule puts a delivery back in the queue.
+func (s *Server) reschedule(d *convoy.EventDeliveryResponse) {
+	now := s.now()
+	d.Status = StatusScheduled
+	d.Metadata.NextSendTime = now
+	d.UpdatedAt = now
+
+	s.schedule(d.UID)
+}
+
+func (s *Server) resendDelivery(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.delivery(w, r)
+	if !ok {
+		return
+	}
+
+	if d.Status != StatusFailure {
+		writeError(w, http.StatusBadRequest, "cannot resend event that did not fail previously")
+		return
+	}
+
+	s.reschedule(d)
+	writeJSON(w, http.StatusOK, "App event processed for retry successfully", d)
+}
+
+// batchRetry retries the failed deliveries matching the query filters.
+func (s *Server) batchRetry(w http.ResponseWriter, r *http.Request) {
+	keep, err := deliveryFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid date: "+err.Error())
+		return
+	}
+
+	retried := 0
+	for _, d := range s.deliveries.newestFirst(keep) {
+		if d.Status == StatusFailure {
+			s.reschedule(d)
+			retried++
+		}
+	}
+
+	writeJSON(w, http.StatusOK, fmt.Sprintf("%d successful, 0 failed", retried), nil)
+}
+
+type forceResendRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// forceResend resends deliveries that already succeeded; any other ID
+// counts as failed.
+func (s *Server) forceResend(w http.ResponseWriter, r *http.Request) {
+	body := &forceResendRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if len(body.IDs) == 0 {
+		writeError(w, http.StatusBadRequest, "please provide event delivery ids")
+		return
+	}
+
+	queued, failed := 0, 0
+	for _, id := range body.IDs {
+		d, ok := s.deliveries.get(id)
+		if !ok || d.Status != StatusSuccess {
+			failed++
+			continue
+		}
+
+		s.reschedule(d)
+		queued++
+	}
+
+	writeJSON(w, http.StatusOK, fmt.Sprintf("%d successful, %d failed", queued, failed), nil)
+}
+
+func (s *Server) listAttempts(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.delivery(w, r)
+	if !ok {
+		return
+	}
+
+	attempts := convoy.ListDeliveryAttemptResponse(s.attempts[d.UID])
+	if attempts == nil {
+		attempts = convoy.ListDeliveryAttemptResponse{}
+	}
+
+	writeJSON(w, http.StatusOK, "Delivery attempts fetched successfully", attempts)
+}
+
+func (s *Server) getAttempt(w http.ResponseWriter, r *http.Request) {
+	d, ok := s.delivery(w, r)
+	if !ok {
+		return
+	}
+
+	for _, a := range s.attempts[d.UID] {
+		if a.UID == r.PathValue("attemptID") {
+			writeJSON(w, http.StatusOK, "Delivery attempt fetched successfully", a)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "delivery attempt not found")
+}
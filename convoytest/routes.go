@@ -0,0 +1,703 @@
+package convoytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+	"github.com/frain-dev/convoy-go/v2/filter"
+)
+
+const projectPath = "/api/v1/projects/{pid}"
+
+func (s *Server) routes() {
+	s.handle("GET "+projectPath, s.getProject)
+	s.handle("PUT "+projectPath, s.updateProject)
+	s.handle("DELETE "+projectPath, s.deleteProject)
+
+	s.handle("GET "+projectPath+"/endpoints", s.listEndpoints)
+	s.handle("POST "+projectPath+"/endpoints", s.createEndpoint)
+	s.handle("GET "+projectPath+"/endpoints/{id}", s.getEndpoint)
+	s.handle("PUT "+projectPath+"/endpoints/{id}", s.updateEndpoint)
+	s.handle("DELETE "+projectPath+"/endpoints/{id}", s.deleteEndpoint)
+	s.handle("PUT "+projectPath+"/endpoints/{id}/pause", s.pauseEndpoint)
+	s.handle("POST "+projectPath+"/endpoints/{id}/activate", s.activateEndpoint)
+	s.handle("PUT "+projectPath+"/endpoints/{id}/expire_secret", s.expireSecret)
+
+	s.handle("GET "+projectPath+"/sources", s.listSources)
+	s.handle("POST "+projectPath+"/sources", s.createSource)
+	s.handle("GET "+projectPath+"/sources/{id}", s.getSource)
+	s.handle("PUT "+projectPath+"/sources/{id}", s.updateSource)
+	s.handle("DELETE "+projectPath+"/sources/{id}", s.deleteSource)
+
+	s.handle("GET "+projectPath+"/subscriptions", s.listSubscriptions)
+	s.handle("POST "+projectPath+"/subscriptions", s.createSubscription)
+	s.handle("GET "+projectPath+"/subscriptions/{id}", s.getSubscription)
+	s.handle("PUT "+projectPath+"/subscriptions/{id}", s.updateSubscription)
+	s.handle("DELETE "+projectPath+"/subscriptions/{id}", s.deleteSubscription)
+	s.handle("PUT "+projectPath+"/subscriptions/{id}/toggle_status", s.toggleSubscription)
+
+	s.handle("GET "+projectPath+"/events", s.listEvents)
+	s.handle("POST "+projectPath+"/events", s.createEvent)
+	s.handle("POST "+projectPath+"/events/fanout", s.fanoutEvent)
+	s.handle("POST "+projectPath+"/events/broadcast", s.broadcastEvent)
+	s.handle("POST "+projectPath+"/events/dynamic", s.dynamicEvent)
+	s.handle("POST "+projectPath+"/events/batchreplay", s.batchReplay)
+	s.handle("GET "+projectPath+"/events/countbatchreplayevents", s.countBatchReplay)
+	s.handle("GET "+projectPath+"/events/{id}", s.getEvent)
+	s.handle("PUT "+projectPath+"/events/{id}/replay", s.replayEvent)
+
+	s.handle("GET "+projectPath+"/eventdeliveries", s.listDeliveries)
+	s.handle("POST "+projectPath+"/eventdeliveries/batchretry", s.batchRetry)
+	s.handle("POST "+projectPath+"/eventdeliveries/forceresend", s.forceResend)
+	s.handle("GET "+projectPath+"/eventdeliveries/{id}", s.getDelivery)
+	s.handle("PUT "+projectPath+"/eventdeliveries/{id}/resend", s.resendDelivery)
+	s.handle("GET "+projectPath+"/eventdeliveries/{id}/deliveryattempts", s.listAttempts)
+	s.handle("GET "+projectPath+"/eventdeliveries/{id}/deliveryattempts/{attemptID}", s.getAttempt)
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "route not found")
+	})
+}
+
+// handle registers a project route. Handlers run under the server lock.
+func (s *Server) handle(pattern string, h http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.project == nil || r.PathValue("pid") != s.ProjectID {
+			writeError(w, http.StatusNotFound, "failed to fetch project")
+			return
+		}
+
+		h(w, r)
+	})
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "request is invalid: "+err.Error())
+		return false
+	}
+
+	return true
+}
+
+// paginate pages through items, newest first, the way Convoy does: the
+// next cursor is the UID of the first item of the next page, and the
+// previous cursor the UID of the first item of this one.
+func paginate[T any](r *http.Request, items []*T, uid func(*T) string) ([]T, convoy.Pagination) {
+	q := r.URL.Query()
+
+	perPage, err := strconv.Atoi(q.Get("perPage"))
+	if err != nil || perPage <= 0 {
+		perPage = DefaultPerPage
+	}
+
+	// UIDs sort in creation order and items are newest first, so the items
+	// at or after a cursor are the ones whose UID is not greater than it.
+	at := func(cursor string) int {
+		return sort.Search(len(items), func(i int) bool { return uid(items[i]) <= cursor })
+	}
+
+	start, end := 0, perPage
+	switch {
+	case q.Get("next_page_cursor") != "":
+		start = at(q.Get("next_page_cursor"))
+		end = start + perPage
+	case q.Get("prev_page_cursor") != "":
+		end = at(q.Get("prev_page_cursor"))
+		start = end - perPage
+	}
+
+	if start < 0 {
+		start = 0
+	}
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page := make([]T, 0, end-start)
+	for _, v := range items[start:end] {
+		page = append(page, *v)
+	}
+
+	p := convoy.Pagination{
+		PerPage:     perPage,
+		HasPrevPage: start > 0,
+		HasNextPage: end < len(items),
+	}
+	if p.HasPrevPage {
+		p.PrevPageCursor = uid(items[start])
+	}
+	if p.HasNextPage {
+		p.NextPageCursor = uid(items[end])
+	}
+
+	return page, p
+}
+
+// dateRange reads the startDate and endDate filters. Zero times, which the
+// client sends for unset dates, are ignored.
+func dateRange(q url.Values) (start, end time.Time, err error) {
+	parse := func(key string) (time.Time, error) {
+		v := q.Get(key)
+		if v == "" {
+			return time.Time{}, nil
+		}
+		t, err := time.Parse("2006-01-02T15:04:05", v)
+		if err != nil || t.IsZero() {
+			return time.Time{}, err
+		}
+		return t, nil
+	}
+
+	start, err = parse("startDate")
+	if err != nil {
+		return start, end, err
+	}
+
+	end, err = parse("endDate")
+	return start, end, err
+}
+
+func inRange(t, start, end time.Time) bool {
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || !t.After(end))
+}
+
+// anyOf reports whether v is one of values; an empty list matches all.
+func anyOf(values []string, v string) bool {
+	return len(values) == 0 || contains(values, v)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, "Project fetched successfully", s.project)
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request) {
+	body := &convoy.CreateProjectRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if body.Name != "" {
+		s.project.Name = body.Name
+	}
+	if body.Type != "" {
+		s.project.Type = body.Type
+	}
+	if body.Project != nil {
+		s.project.Config = body.Project
+	}
+	s.project.LogoUrl = body.LogoUrl
+	s.project.RateLimit = body.RateLimit
+	s.project.RateLimitDuration = body.RateLimitDuration
+	s.project.UpdatedAt = s.now()
+
+	writeJSON(w, http.StatusAccepted, "Project updated successfully", s.project)
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	s.project = nil
+	writeJSON(w, http.StatusOK, "Project deleted successfully", nil)
+}
+
+func (s *Server) listEndpoints(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	owner, search := q.Get("ownerId"), strings.ToLower(q.Get("query"))
+
+	items := s.endpoints.newestFirst(func(e *convoy.EndpointResponse) bool {
+		return (owner == "" || e.OwnerID == owner) &&
+			(search == "" || strings.Contains(strings.ToLower(e.Name), search))
+	})
+
+	page, p := paginate(r, items, func(e *convoy.EndpointResponse) string { return e.UID })
+	writeJSON(w, http.StatusOK, "Endpoints fetched successfully", convoy.ListEndpointResponse{Content: page, Pagination: p})
+}
+
+func (s *Server) createEndpoint(w http.ResponseWriter, r *http.Request) {
+	body := &convoy.CreateEndpointRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := validateEndpoint(body); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, "Endpoint created successfully", s.addEndpoint(body))
+}
+
+func (s *Server) addEndpoint(body *convoy.CreateEndpointRequest) *convoy.EndpointResponse {
+	secret := body.Secret
+	if secret == "" {
+		secret = newSecret()
+	}
+
+	now := s.now()
+	e := &convoy.EndpointResponse{
+		UID:                s.newID("ep"),
+		ProjectID:          s.ProjectID,
+		AdvancedSignatures: true,
+		Secrets:            []convoy.Secret{{UID: s.newID("secret"), Value: secret}},
+		CreatedAt:          now,
+	}
+	applyEndpoint(e, body, now)
+
+	s.endpoints.put(e.UID, e)
+	return e
+}
+
+func validateEndpoint(body *convoy.CreateEndpointRequest) string {
+	if strings.TrimSpace(body.Name) == "" {
+		return "please provide your endpoint name"
+	}
+
+	u, err := url.Parse(body.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "please provide a valid endpoint url"
+	}
+
+	return ""
+}
+
+func applyEndpoint(e *convoy.EndpointResponse, body *convoy.CreateEndpointRequest, now time.Time) {
+	e.Name = body.Name
+	e.URL = body.URL
+	e.OwnerID = body.OwnerID
+	e.Description = body.Description
+	e.SupportEmail = body.SupportEmail
+	e.SlackWebhookUrl = body.SlackWebhookUrl
+	e.Authentication = body.Authentication
+	e.MtlsClientCert = body.MtlsClientCert
+	e.HttpTimeout = body.HttpTimeout
+	e.RateLimit = body.RateLimit
+	e.RateLimitDuration = body.RateLimitDuration
+
+	e.ContentType = body.ContentType
+	if e.ContentType == "" {
+		e.ContentType = "application/json"
+	}
+
+	if body.AdvancedSignatures != nil {
+		e.AdvancedSignatures = *body.AdvancedSignatures
+	}
+
+	switch {
+	case body.IsDisabled:
+		e.Status = convoy.EndpointStatusInactive
+	case e.Status == "" || e.Status == convoy.EndpointStatusInactive:
+		e.Status = convoy.EndpointStatusActive
+	}
+
+	e.UpdatedAt = now
+}
+
+func (s *Server) endpoint(w http.ResponseWriter, r *http.Request) (*convoy.EndpointResponse, bool) {
+	e, ok := s.endpoints.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "endpoint not found")
+	}
+
+	return e, ok
+}
+
+func (s *Server) getEndpoint(w http.ResponseWriter, r *http.Request) {
+	if e, ok := s.endpoint(w, r); ok {
+		writeJSON(w, http.StatusOK, "Endpoint fetched successfully", e)
+	}
+}
+
+func (s *Server) updateEndpoint(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.endpoint(w, r)
+	if !ok {
+		return
+	}
+
+	body := &convoy.CreateEndpointRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := validateEndpoint(body); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	applyEndpoint(e, body, s.now())
+	writeJSON(w, http.StatusAccepted, "Endpoint updated successfully", e)
+}
+
+func (s *Server) deleteEndpoint(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.endpoint(w, r)
+	if !ok {
+		return
+	}
+
+	s.endpoints.delete(e.UID)
+	for _, sub := range s.subscriptions.oldestFirst() {
+		if sub.endpointID == e.UID {
+			s.subscriptions.delete(sub.UID)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, "Endpoint deleted successfully", nil)
+}
+
+// pauseEndpoint toggles between paused and active, as Convoy does.
+func (s *Server) pauseEndpoint(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.endpoint(w, r)
+	if !ok {
+		return
+	}
+
+	if e.Status == convoy.EndpointStatusPaused {
+		e.Status = convoy.EndpointStatusActive
+	} else {
+		e.Status = convoy.EndpointStatusPaused
+	}
+	e.UpdatedAt = s.now()
+
+	writeJSON(w, http.StatusAccepted, "endpoint status updated successfully", e)
+}
+
+func (s *Server) activateEndpoint(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.endpoint(w, r)
+	if !ok {
+		return
+	}
+
+	e.Status = convoy.EndpointStatusActive
+	e.UpdatedAt = s.now()
+
+	writeJSON(w, http.StatusAccepted, "endpoint status updated successfully", e)
+}
+
+func (s *Server) expireSecret(w http.ResponseWriter, r *http.Request) {
+	e, ok := s.endpoint(w, r)
+	if !ok {
+		return
+	}
+
+	body := &convoy.RollSecretRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if body.Expiration < 0 {
+		writeError(w, http.StatusBadRequest, "expiration can't be negative")
+		return
+	}
+
+	now := s.now()
+	for i := range e.Secrets {
+		if e.Secrets[i].ExpiresAt.IsZero() {
+			e.Secrets[i].ExpiresAt = now.Add(time.Duration(body.Expiration) * time.Hour)
+		}
+	}
+
+	secret := body.Secret
+	if secret == "" {
+		secret = newSecret()
+	}
+	e.Secrets = append(e.Secrets, convoy.Secret{UID: s.newID("secret"), Value: secret})
+	e.UpdatedAt = now
+
+	writeJSON(w, http.StatusOK, "endpoint secret expired successfully", e)
+}
+
+func (s *Server) listSources(w http.ResponseWriter, r *http.Request) {
+	typ := r.URL.Query().Get("type")
+
+	items := s.sources.newestFirst(func(src *convoy.SourceResponse) bool {
+		return typ == "" || src.Type == typ
+	})
+
+	page, p := paginate(r, items, func(src *convoy.SourceResponse) string { return src.UID })
+	writeJSON(w, http.StatusOK, "Sources fetched successfully", convoy.ListSourceResponse{Content: page, Pagination: p})
+}
+
+func (s *Server) createSource(w http.ResponseWriter, r *http.Request) {
+	body := &convoy.CreateSourceRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := validateSource(body); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	now := s.now()
+	src := &convoy.SourceResponse{
+		UID:       s.newID("src"),
+		ProjectID: s.ProjectID,
+		MaskID:    newSecret()[:16],
+		CreatedAt: now,
+	}
+	src.URL = s.ts.URL + "/ingest/" + src.MaskID
+	applySource(src, body, now)
+
+	s.sources.put(src.UID, src)
+	writeJSON(w, http.StatusCreated, "Source created successfully", src)
+}
+
+func validateSource(body *convoy.CreateSourceRequest) string {
+	if strings.TrimSpace(body.Name) == "" {
+		return "please provide a source name"
+	}
+
+	if body.Type == "" {
+		return "please provide a source type"
+	}
+
+	return ""
+}
+
+func applySource(src *convoy.SourceResponse, body *convoy.CreateSourceRequest, now time.Time) {
+	verifier := body.Verifier
+
+	src.Name = body.Name
+	src.Type = body.Type
+	src.Provider = body.Provider
+	src.IsDisabled = body.IsDisabled
+	src.Verifier = &verifier
+	src.BodyFunction = body.BodyFunction
+	src.HeaderFunction = body.HeaderFunction
+	src.CustomResponse = body.CustomResponse
+	src.IdempotencyKeys = body.IdempotencyKeys
+	src.EventTypeLocation = body.EventTypeLocation
+	src.PubSub = body.PubSub
+	src.UpdatedAt = now
+}
+
+func (s *Server) source(w http.ResponseWriter, r *http.Request) (*convoy.SourceResponse, bool) {
+	src, ok := s.sources.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "source not found")
+	}
+
+	return src, ok
+}
+
+func (s *Server) getSource(w http.ResponseWriter, r *http.Request) {
+	if src, ok := s.source(w, r); ok {
+		writeJSON(w, http.StatusOK, "Source fetched successfully", src)
+	}
+}
+
+func (s *Server) updateSource(w http.ResponseWriter, r *http.Request) {
+	src, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+
+	body := &convoy.CreateSourceRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := validateSource(body); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	applySource(src, body, s.now())
+	writeJSON(w, http.StatusAccepted, "Source updated successfully", src)
+}
+
+func (s *Server) deleteSource(w http.ResponseWriter, r *http.Request) {
+	src, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+
+	s.sources.delete(src.UID)
+	writeJSON(w, http.StatusOK, "Source deleted successfully", nil)
+}
+
+func (s *Server) listSubscriptions(w http.ResponseWriter, r *http.Request) {
+	endpointIDs := r.URL.Query()["endpointId"]
+
+	items := s.subscriptions.newestFirst(func(sub *subscription) bool {
+		return anyOf(endpointIDs, sub.endpointID)
+	})
+
+	page, p := paginate(r, items, func(sub *subscription) string { return sub.UID })
+
+	content := make([]convoy.SubscriptionResponse, len(page))
+	for i := range page {
+		content[i] = s.subscriptionResponse(&page[i])
+	}
+
+	writeJSON(w, http.StatusOK, "Subscriptions fetched successfully", convoy.ListSubscriptionResponse{Content: content, Pagination: p})
+}
+
+// subscriptionResponse returns the subscription with its endpoint and
+// source metadata.
+func (s *Server) subscriptionResponse(sub *subscription) convoy.SubscriptionResponse {
+	resp := sub.SubscriptionResponse
+	if e, ok := s.endpoints.get(sub.endpointID); ok {
+		ep := *e
+		resp.Endpoint = &ep
+	}
+	if src, ok := s.sources.get(sub.sourceID); ok {
+		source := *src
+		resp.Source = &source
+	}
+
+	return resp
+}
+
+func (s *Server) createSubscription(w http.ResponseWriter, r *http.Request) {
+	body := &convoy.CreateSubscriptionRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if msg := s.validateSubscription(body); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	sub := s.addSubscription(body)
+	writeJSON(w, http.StatusCreated, "Subscription created successfully", s.subscriptionResponse(sub))
+}
+
+func (s *Server) addSubscription(body *convoy.CreateSubscriptionRequest) *subscription {
+	now := s.now()
+	sub := &subscription{SubscriptionResponse: convoy.SubscriptionResponse{
+		UID:       s.newID("sub"),
+		Type:      "api",
+		Status:    "active",
+		CreatedAt: now,
+	}}
+	applySubscription(sub, body, now)
+
+	s.subscriptions.put(sub.UID, sub)
+	return sub
+}
+
+func (s *Server) validateSubscription(body *convoy.CreateSubscriptionRequest) string {
+	if strings.TrimSpace(body.Name) == "" {
+		return "please provide a valid subscription name"
+	}
+
+	if _, ok := s.endpoints.get(body.EndpointID); !ok {
+		return "failed to find endpoint"
+	}
+
+	if body.SourceID != "" {
+		if _, ok := s.sources.get(body.SourceID); !ok {
+			return "failed to find source"
+		}
+	}
+
+	if body.FilterConfig != nil {
+		_, err := filter.Match(body.FilterConfig.Filter.Body, json.RawMessage(`{}`))
+		if err == nil {
+			_, err = filter.MatchHeaders(body.FilterConfig.Filter.Headers, nil)
+		}
+		if err != nil {
+			return err.Error()
+		}
+	}
+
+	return ""
+}
+
+func applySubscription(sub *subscription, body *convoy.CreateSubscriptionRequest, now time.Time) {
+	sub.endpointID = body.EndpointID
+	sub.sourceID = body.SourceID
+
+	sub.Name = body.Name
+	sub.AlertConfig = body.AlertConfig
+	sub.RetryConfig = body.RetryConfig
+	sub.RateLimitConfig = body.RateLimitConfig
+	sub.Function = body.Function
+
+	sub.FilterConfig = body.FilterConfig
+	if sub.FilterConfig == nil {
+		sub.FilterConfig = &convoy.FilterConfiguration{EventTypes: []string{"*"}}
+	}
+
+	sub.DeliveryMode = body.DeliveryMode
+	if sub.DeliveryMode == "" {
+		sub.DeliveryMode = convoy.DeliveryModeAtLeastOnce
+	}
+
+	sub.UpdatedAt = now
+}
+
+func (s *Server) subscription(w http.ResponseWriter, r *http.Request) (*subscription, bool) {
+	sub, ok := s.subscriptions.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "subscription not found")
+	}
+
+	return sub, ok
+}
+
+func (s *Server) getSubscription(w http.ResponseWriter, r *http.Request) {
+	if sub, ok := s.subscription(w, r); ok {
+		writeJSON(w, http.StatusOK, "Subscription fetched successfully", s.subscriptionResponse(sub))
+	}
+}
+
+func (s *Server) updateSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.subscription(w, r)
+	if !ok {
+		return
+	}
+
+	body := &convoy.CreateSubscriptionRequest{}
+	if !decode(w, r, body) {
+		return
+	}
+
+	if body.EndpointID == "" {
+		body.EndpointID = sub.endpointID
+	}
+
+	if msg := s.validateSubscription(body); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	applySubscription(sub, body, s.now())
+	writeJSON(w, http.StatusAccepted, "Subscription updated successfully", s.subscriptionResponse(sub))
+}
+
+func (s *Server) deleteSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.subscription(w, r)
+	if !ok {
+		return
+	}
+
+	s.subscriptions.delete(sub.UID)
+	writeJSON(w, http.StatusOK, "Subscription deleted successfully", nil)
+}
+
+func (s *Server) toggleSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, ok := s.subscription(w, r)
+	if !ok {
+		return
+	}
+
+	if sub.Status == "active" {
+		sub.Status = "inactive"
+	} else {
+		sub.Status = "active"
+	}
+	sub.UpdatedAt = s.now()
+
+	writeJSON(w, http.StatusAccepted, "Subscription status updated successfully", s.subscriptionResponse(sub))
+}
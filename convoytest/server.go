@@ -0,0 +1,452 @@
+// Package convoytest provides an in-memory Convoy API for tests.
+//
+// A Server implements the project, endpoint, source, subscription, event,
+// event delivery and delivery attempt routes that convoy_go.Client calls,
+// with Convoy's {status,message,data} envelopes, cursor pagination and
+// error statuses, so code built on the client can be exercised end to end
+// without a Convoy instance:
+//
+//	srv := convoytest.NewServer()
+//	defer srv.Close()
+//
+//	c := srv.Client()
+//	// ... run the code under test with c ...
+//
+//	srv.ExpectEvent(t, convoytest.EventQuery{EventType: "invoice.paid"})
+//
+// Events are matched to subscriptions with the filter package and get one
+// delivery per matched subscription. An endpoint without subscriptions
+// receives every event sent to it directly or by fan-out; broadcasts only
+// reach endpoints through their subscriptions. Deliveries stay Scheduled
+// unless the server is started with OptionDelivery, which sends them to the
+// endpoint URLs.
+package convoytest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+)
+
+const (
+	DefaultProjectID = "test-project"
+	DefaultAPIKey    = "test-api-key"
+)
+
+// DefaultPerPage is the page size of list routes when the request sets none.
+var DefaultPerPage = 20
+
+// Server is an in-memory Convoy API serving a single project. Use the
+// client from Client, or point any client at URL with APIKey and ProjectID.
+type Server struct {
+	// URL is the API base URL, i.e. what convoy_go.New takes.
+	URL       string
+	ProjectID string
+	APIKey    string
+
+	ts           *httptest.Server
+	mux          *http.ServeMux
+	deliveryOpts *DeliveryOptions
+
+	stop   context.Context
+	cancel context.CancelFunc
+
+	mu            sync.Mutex
+	seq           int
+	project       *convoy.ProjectResponse
+	endpoints     *store[convoy.EndpointResponse]
+	sources       *store[convoy.SourceResponse]
+	subscriptions *store[subscription]
+	events        *store[event]
+	deliveries    *store[convoy.EventDeliveryResponse]
+	attempts      map[string][]convoy.DeliveryAttemptResponse
+	failures      []failure
+
+	// inflight counts deliveries being sent or waiting for a retry.
+	inflight int
+	idle     *sync.Cond
+}
+
+// subscription keeps the IDs a subscription links to; the response only
+// carries them as metadata.
+type subscription struct {
+	convoy.SubscriptionResponse
+	endpointID string
+	sourceID   string
+}
+
+// event keeps the headers an event was sent with, for header filters.
+type event struct {
+	convoy.EventResponse
+	headers http.Header
+}
+
+type failure struct {
+	status  int
+	message string
+}
+
+type Option func(*Server)
+
+// NewServer starts a Server. Close it when the test ends.
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		ProjectID: DefaultProjectID,
+		APIKey:    DefaultAPIKey,
+		mux:       http.NewServeMux(),
+	}
+	s.idle = sync.NewCond(&s.mu)
+	s.stop, s.cancel = context.WithCancel(context.Background())
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	s.reset()
+	s.routes()
+
+	s.ts = httptest.NewServer(s)
+	s.URL = s.ts.URL + "/api/v1"
+
+	return s
+}
+
+// Close shuts the server down, abandoning pending retries.
+func (s *Server) Close() {
+	s.cancel()
+	s.ts.Close()
+	s.Wait()
+}
+
+// Client returns a client for the server's project.
+func (s *Server) Client(options ...convoy.Option) *convoy.Client {
+	return convoy.New(s.URL, s.APIKey, s.ProjectID, options...)
+}
+
+// ServeHTTP checks the API key, applies failures queued with FailNext and
+// routes the request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+s.APIKey {
+		writeError(w, http.StatusUnauthorized, "invalid api key")
+		return
+	}
+
+	s.mu.Lock()
+	var f *failure
+	if len(s.failures) > 0 {
+		f = &s.failures[0]
+		s.failures = s.failures[1:]
+	}
+	s.mu.Unlock()
+
+	if f != nil {
+		writeError(w, f.status, f.message)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// FailNext makes the next request fail with status and message, whatever
+// its route. Calls queue up, one failure per request.
+func (s *Server) FailNext(status int, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failure{status: status, message: message})
+}
+
+// Reset drops every resource and queued failure.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reset()
+}
+
+func (s *Server) reset() {
+	now := s.now()
+	s.seq = 0
+	s.project = &convoy.ProjectResponse{
+		UID:  s.ProjectID,
+		Name: "convoytest",
+		Type: "outgoing",
+		Config: &convoy.ProjectConfig{
+			Strategy:  &convoy.StrategyConfiguration{Type: "linear", Duration: 10, RetryCount: 3},
+			Signature: &convoy.SignatureConfiguration{Header: convoy.DefaultSigHeader, Hash: convoy.DefaultHash},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.endpoints = newStore[convoy.EndpointResponse]()
+	s.sources = newStore[convoy.SourceResponse]()
+	s.subscriptions = newStore[subscription]()
+	s.events = newStore[event]()
+	s.deliveries = newStore[convoy.EventDeliveryResponse]()
+	s.attempts = map[string][]convoy.DeliveryAttemptResponse{}
+	s.failures = nil
+}
+
+// Events returns the events sent to the server, oldest first.
+func (s *Server) Events() []convoy.EventResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []convoy.EventResponse
+	for _, e := range s.events.oldestFirst() {
+		out = append(out, e.EventResponse)
+	}
+
+	return out
+}
+
+// Deliveries returns the event deliveries created so far, oldest first.
+func (s *Server) Deliveries() []convoy.EventDeliveryResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []convoy.EventDeliveryResponse
+	for _, d := range s.deliveries.oldestFirst() {
+		out = append(out, *d)
+	}
+
+	return out
+}
+
+// Endpoints returns the project's endpoints, oldest first.
+func (s *Server) Endpoints() []convoy.EndpointResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []convoy.EndpointResponse
+	for _, e := range s.endpoints.oldestFirst() {
+		out = append(out, *e)
+	}
+
+	return out
+}
+
+// Subscriptions returns the project's subscriptions, oldest first.
+func (s *Server) Subscriptions() []convoy.SubscriptionResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []convoy.SubscriptionResponse
+	for _, sub := range s.subscriptions.oldestFirst() {
+		out = append(out, s.subscriptionResponse(sub))
+	}
+
+	return out
+}
+
+// EventQuery selects events in ExpectEvent and ExpectNoEvent. Empty fields
+// match any event.
+type EventQuery struct {
+	EventType      string
+	EndpointID     string
+	IdempotencyKey string
+	// Match, when set, must also accept the event.
+	Match func(convoy.EventResponse) bool
+}
+
+func (q EventQuery) matches(e convoy.EventResponse) bool {
+	if q.EventType != "" && q.EventType != e.EventType {
+		return false
+	}
+
+	if q.IdempotencyKey != "" && q.IdempotencyKey != e.IdempotencyKey {
+		return false
+	}
+
+	if q.EndpointID != "" && !contains(e.Endpoints, q.EndpointID) {
+		return false
+	}
+
+	return q.Match == nil || q.Match(e)
+}
+
+func (q EventQuery) String() string {
+	var parts []string
+	if q.EventType != "" {
+		parts = append(parts, "event_type="+q.EventType)
+	}
+	if q.EndpointID != "" {
+		parts = append(parts, "endpoint="+q.EndpointID)
+	}
+	if q.IdempotencyKey != "" {
+		parts = append(parts, "idempotency_key="+q.IdempotencyKey)
+	}
+	if q.Match != nil {
+		parts = append(parts, "match=func")
+	}
+	if len(parts) == 0 {
+		return "any event"
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// ExpectEvent fails the test unless an event matches q, and returns the
+// oldest matching event.
+func (s *Server) ExpectEvent(t testing.TB, q EventQuery) convoy.EventResponse {
+	t.Helper()
+
+	events := s.Events()
+	for _, e := range events {
+		if q.matches(e) {
+			return e
+		}
+	}
+
+	t.Fatalf("convoytest: no event matches %s; got %s", q, describeEvents(events))
+	return convoy.EventResponse{}
+}
+
+// ExpectNoEvent fails the test if any event matches q.
+func (s *Server) ExpectNoEvent(t testing.TB, q EventQuery) {
+	t.Helper()
+
+	var matched []convoy.EventResponse
+	for _, e := range s.Events() {
+		if q.matches(e) {
+			matched = append(matched, e)
+		}
+	}
+
+	if len(matched) > 0 {
+		t.Errorf("convoytest: expected no event to match %s; got %s", q, describeEvents(matched))
+	}
+}
+
+func describeEvents(events []convoy.EventResponse) string {
+	if len(events) == 0 {
+		return "no events"
+	}
+
+	parts := make([]string, len(events))
+	for i, e := range events {
+		parts[i] = fmt.Sprintf("%s (%s -> %s)", e.UID, e.EventType, strings.Join(e.Endpoints, ","))
+	}
+
+	return fmt.Sprintf("%d events: %s", len(events), strings.Join(parts, "; "))
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().UTC()
+}
+
+// newID returns sequential IDs that sort in creation order, which the
+// pagination cursors rely on.
+func (s *Server) newID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s_%08d", prefix, s.seq)
+}
+
+func newSecret() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// store keeps resources in creation order.
+type store[T any] struct {
+	ids   []string
+	items map[string]*T
+}
+
+func newStore[T any]() *store[T] {
+	return &store[T]{items: map[string]*T{}}
+}
+
+func (s *store[T]) put(id string, v *T) {
+	if _, ok := s.items[id]; !ok {
+		s.ids = append(s.ids, id)
+	}
+
+	s.items[id] = v
+}
+
+func (s *store[T]) get(id string) (*T, bool) {
+	v, ok := s.items[id]
+	return v, ok
+}
+
+func (s *store[T]) delete(id string) {
+	if _, ok := s.items[id]; !ok {
+		return
+	}
+
+	delete(s.items, id)
+	for i, existing := range s.ids {
+		if existing == id {
+			s.ids = append(s.ids[:i], s.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *store[T]) oldestFirst() []*T {
+	out := make([]*T, len(s.ids))
+	for i, id := range s.ids {
+		out[i] = s.items[id]
+	}
+
+	return out
+}
+
+// newestFirst returns the items matching keep in the order list routes
+// page through them.
+func (s *store[T]) newestFirst(keep func(*T) bool) []*T {
+	var out []*T
+	for i := len(s.ids) - 1; i >= 0; i-- {
+		v := s.items[s.ids[i]]
+		if keep == nil || keep(v) {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, message string, data interface{}) {
+	resp := convoy.APIResponse{Status: true, Message: message}
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		raw := json.RawMessage(b)
+		resp.Data = &raw
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(convoy.APIResponse{Status: false, Message: message})
+}
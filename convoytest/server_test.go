@@ -0,0 +1,225 @@
+package convoytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerDeliversEventsToMatchingSubscriptions(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := srv.Client()
+
+	ep, err := c.Endpoints.Create(ctx, &convoy.CreateEndpointRequest{Name: "billing", URL: "https://example.com/hook"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, convoy.EndpointStatusActive, ep.Status)
+	require.Len(t, ep.Secrets, 1)
+
+	sub, err := c.Subscriptions.Create(ctx, &convoy.CreateSubscriptionRequest{
+		Name:       "large-invoices",
+		EndpointID: ep.UID,
+		FilterConfig: &convoy.FilterConfiguration{
+			EventTypes: []string{"invoice.paid"},
+			Filter:     convoy.Filter{Body: map[string]interface{}{"amount": map[string]interface{}{"$gte": 100}}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ep.UID, sub.Endpoint.UID)
+
+	large, err := c.Events.Create(ctx, &convoy.CreateEventRequest{
+		EndpointID:     ep.UID,
+		EventType:      "invoice.paid",
+		IdempotencyKey: "inv-1",
+		Data:           []byte(`{"amount": 150}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, large.MatchedEndpoints)
+
+	small, err := c.Events.Create(ctx, &convoy.CreateEventRequest{
+		EndpointID: ep.UID,
+		EventType:  "invoice.paid",
+		Data:       []byte(`{"amount": 10}`),
+	})
+	require.NoError(t, err)
+	require.Zero(t, small.MatchedEndpoints)
+
+	got := srv.ExpectEvent(t, EventQuery{EventType: "invoice.paid", IdempotencyKey: "inv-1"})
+	require.Equal(t, large.UID, got.UID)
+	srv.ExpectNoEvent(t, EventQuery{EventType: "invoice.created"})
+
+	deliveries, err := c.EventDeliveries.All(ctx, &convoy.EventDeliveryParams{EventID: large.UID})
+	require.NoError(t, err)
+	require.Len(t, deliveries.Content, 1)
+	require.Equal(t, sub.UID, deliveries.Content[0].SubscriptionID)
+	require.Equal(t, StatusScheduled, deliveries.Content[0].Status)
+	require.Len(t, srv.Deliveries(), 1)
+
+	dup, err := c.Events.Create(ctx, &convoy.CreateEventRequest{
+		EndpointID:     ep.UID,
+		EventType:      "invoice.paid",
+		IdempotencyKey: "inv-1",
+		Data:           []byte(`{"amount": 150}`),
+	})
+	require.NoError(t, err)
+	require.True(t, dup.IsDuplicateEvent)
+	require.Len(t, srv.Deliveries(), 1)
+}
+
+func TestServerFansOutAndBroadcasts(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := srv.Client()
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		ep, err := c.Endpoints.Create(ctx, &convoy.CreateEndpointRequest{
+			Name:    fmt.Sprintf("ep-%d", i),
+			URL:     "https://example.com/hook",
+			OwnerID: "tenant-1",
+		}, nil)
+		require.NoError(t, err)
+		ids = append(ids, ep.UID)
+	}
+
+	event, err := c.Events.FanoutEvent(ctx, &convoy.CreateFanoutEventRequest{
+		OwnerID:   "tenant-1",
+		EventType: "user.created",
+		Data:      []byte(`{}`),
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, ids, event.Endpoints)
+
+	_, err = c.Subscriptions.Create(ctx, &convoy.CreateSubscriptionRequest{
+		Name:         "all",
+		EndpointID:   ids[1],
+		FilterConfig: &convoy.FilterConfiguration{EventTypes: []string{"*"}},
+	})
+	require.NoError(t, err)
+
+	resp, err := c.Events.BroadcastEvent(ctx, &convoy.CreateBroadcastEventRequest{
+		EventType: "user.deleted",
+		Data:      []byte(`{}`),
+	})
+	require.NoError(t, err)
+	require.True(t, resp.Accepted)
+	require.Equal(t, []string{ids[1]}, resp.Event.Endpoints)
+
+	srv.ExpectEvent(t, EventQuery{EventType: "user.deleted", EndpointID: ids[1]})
+	srv.ExpectNoEvent(t, EventQuery{EventType: "user.deleted", EndpointID: ids[0]})
+}
+
+func TestServerPaginatesWithCursors(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := srv.Client()
+
+	for i := 0; i < 5; i++ {
+		_, err := c.Endpoints.Create(ctx, &convoy.CreateEndpointRequest{Name: fmt.Sprintf("ep-%d", i), URL: "https://example.com"}, nil)
+		require.NoError(t, err)
+	}
+
+	var names []string
+	params := &convoy.EndpointParams{ListParams: convoy.ListParams{PerPage: 2}}
+	for {
+		page, err := c.Endpoints.All(ctx, params)
+		require.NoError(t, err)
+
+		for _, ep := range page.Content {
+			names = append(names, ep.Name)
+		}
+
+		if !page.Pagination.HasNextPage {
+			break
+		}
+		params.NextPageCursor = page.Pagination.NextPageCursor
+	}
+	require.Equal(t, []string{"ep-4", "ep-3", "ep-2", "ep-1", "ep-0"}, names)
+
+	// Step back from the last page.
+	last, err := c.Endpoints.All(ctx, params)
+	require.NoError(t, err)
+	require.True(t, last.Pagination.HasPrevPage)
+
+	prev, err := c.Endpoints.All(ctx, &convoy.EndpointParams{ListParams: convoy.ListParams{
+		PerPage:        2,
+		PrevPageCursor: last.Pagination.PrevPageCursor,
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "ep-2", prev.Content[0].Name)
+	require.Equal(t, "ep-1", prev.Content[1].Name)
+}
+
+func TestServerErrors(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	var apiErr *convoy.APIError
+
+	_, err := convoy.New(srv.URL, "wrong-key", srv.ProjectID).Endpoints.All(ctx, nil)
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+
+	c := srv.Client()
+
+	_, err = c.Endpoints.Find(ctx, "missing", nil)
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	_, err = c.Endpoints.Create(ctx, &convoy.CreateEndpointRequest{Name: "no-url"}, nil)
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+
+	srv.FailNext(http.StatusServiceUnavailable, "maintenance")
+	_, err = c.Endpoints.All(ctx, nil)
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	require.Equal(t, "maintenance", apiErr.Message)
+
+	_, err = c.Endpoints.All(ctx, nil)
+	require.NoError(t, err)
+}
+
+func TestServerResendsDeliveries(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := srv.Client()
+
+	ep, err := c.Endpoints.Create(ctx, &convoy.CreateEndpointRequest{Name: "billing", URL: "https://example.com"}, nil)
+	require.NoError(t, err)
+
+	_, err = c.Events.Create(ctx, &convoy.CreateEventRequest{EndpointID: ep.UID, EventType: "invoice.paid", Data: []byte(`{}`)})
+	require.NoError(t, err)
+
+	d := srv.Deliveries()[0]
+	_, err = c.EventDeliveries.Resend(ctx, d.UID, nil)
+	require.Error(t, err, "only failed deliveries can be resent")
+
+	srv.mu.Lock()
+	srv.deliveries.items[d.UID].Status = StatusSuccess
+	srv.mu.Unlock()
+
+	result, err := c.EventDeliveries.ForceResend(ctx, []string{d.UID, "missing"})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.Queued)
+	require.Equal(t, 1, result.Failed)
+
+	n, err := c.Events.CountAffected(ctx, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+}
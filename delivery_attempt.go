@@ -12,6 +12,13 @@ var (
 	ErrNotDeliveryAttemptResponse     = errors.New("invalid delivery attempt response")
 )
 
+// DeliveryAttemptService is the delivery attempts API. *DeliveryAttempt
+// implements it; convoymock has a mock.
+type DeliveryAttemptService interface {
+	All(ctx context.Context, eventDeliveryID string, query *DeliveryAttemptQueryParam) (*ListDeliveryAttemptResponse, error)
+	Find(ctx context.Context, eventDeliveryID, deliveryAttemptID string, query *DeliveryAttemptQueryParam) (*DeliveryAttemptResponse, error)
+}
+
 type DeliveryAttempt struct {
 	client *Client
 }
@@ -12,6 +12,35 @@ var (
 	ErrNotEndpointResponse     = errors.New("invalid endpoint response")
 )
 
+// EndpointStatus is an endpoint's delivery status.
+type EndpointStatus string
+
+const (
+	EndpointStatusActive   EndpointStatus = "active"
+	EndpointStatusInactive EndpointStatus = "inactive"
+	EndpointStatusPaused   EndpointStatus = "paused"
+)
+
+// DefaultHealthFailureLimit is how many recent failed deliveries
+// Endpoint.Health returns.
+var DefaultHealthFailureLimit = 10
+
+// EndpointService is the endpoints API. *Endpoint implements it; convoymock
+// has a mock.
+type EndpointService interface {
+	All(ctx context.Context, query *EndpointParams) (*ListEndpointResponse, error)
+	Create(ctx context.Context, body *CreateEndpointRequest, query *EndpointParams) (*EndpointResponse, error)
+	Find(ctx context.Context, endpointID string, query *EndpointParams) (*EndpointResponse, error)
+	Update(ctx context.Context, endpointID string, body *CreateEndpointRequest, query *EndpointParams) (*EndpointResponse, error)
+	Delete(ctx context.Context, endpointID string, query *EndpointParams) error
+	Pause(ctx context.Context, Id string) (*EndpointResponse, error)
+	Activate(ctx context.Context, Id string) (*EndpointResponse, error)
+	Health(ctx context.Context, Id string) (*EndpointHealth, error)
+	RollSecret(ctx context.Context, Id string, body *RollSecretRequest) error
+	ExpireSecret(ctx context.Context, Id string, body *RollSecretRequest) (*EndpointResponse, error)
+	TestOAuth2Connection(ctx context.Context, body *OAuth2Auth) (*OAuth2TestResponse, error)
+}
+
 type Endpoint struct {
 	client *Client
 }
@@ -28,7 +57,8 @@ type CreateEndpointRequest struct {
 	IsDisabled         bool   `json:"is_disabled"`
 	ContentType        string `json:"content_type,omitempty"`
 
-	Authentication *EndpointAuth `json:"authentication,omitempty"`
+	Authentication *EndpointAuth   `json:"authentication,omitempty"`
+	MtlsClientCert *MtlsClientCert `json:"mtls_client_cert,omitempty"`
 
 	// HttpTimeout is the endpoint request timeout in seconds.
 	HttpTimeout uint64 `json:"http_timeout,omitempty"`
@@ -45,12 +75,12 @@ type EndpointResponse struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 
-	Status             string   `json:"status"`
-	Secrets            []Secret `json:"secrets"`
-	AdvancedSignatures bool     `json:"advanced_signatures"`
-	SlackWebhookUrl    string   `json:"slack_webhook_url"`
-	SupportEmail       string   `json:"support_email"`
-	ContentType        string   `json:"content_type"`
+	Status             EndpointStatus `json:"status"`
+	Secrets            []Secret       `json:"secrets"`
+	AdvancedSignatures bool           `json:"advanced_signatures"`
+	SlackWebhookUrl    string         `json:"slack_webhook_url"`
+	SupportEmail       string         `json:"support_email"`
+	ContentType        string         `json:"content_type"`
 
 	// HttpTimeout is the endpoint request timeout in seconds.
 	HttpTimeout uint64 `json:"http_timeout"`
@@ -58,8 +88,9 @@ type EndpointResponse struct {
 	// RateLimitDuration is the rate limit window in seconds.
 	RateLimitDuration uint64 `json:"rate_limit_duration"`
 
-	Authentication *EndpointAuth `json:"authentication"`
-	Events         int64         `json:"events"`
+	Authentication *EndpointAuth   `json:"authentication"`
+	MtlsClientCert *MtlsClientCert `json:"mtls_client_cert"`
+	Events         int64           `json:"events"`
 
 	// FailureRate is the circuit breaker's rolling failure rate; null when the
 	// feature is off or has no sample for this endpoint.
@@ -72,9 +103,32 @@ type EndpointResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// EndpointHealth summarizes an endpoint for on-call tooling.
+type EndpointHealth struct {
+	EndpointID string
+	Status     EndpointStatus
+	// CBState and FailureRate are empty and nil when the circuit breaker
+	// has no sample for the endpoint.
+	CBState     string
+	FailureRate *float64
+	// RecentFailures are the endpoint's latest failed deliveries, newest
+	// first.
+	RecentFailures []EventDeliveryResponse
+}
+
+// Healthy reports whether the endpoint is active and its circuit breaker
+// isn't open.
+func (h *EndpointHealth) Healthy() bool {
+	return h.Status == EndpointStatusActive && h.CBState != "open"
+}
+
 type EndpointAuth struct {
-	Type   string      `json:"type"`
-	ApiKey *ApiKeyAuth `json:"api_key"`
+	// Type is one of EndpointAuthAPIKey, EndpointAuthBasicAuth or
+	// EndpointAuthOAuth2, and selects the matching config below.
+	Type      string      `json:"type"`
+	ApiKey    *ApiKeyAuth `json:"api_key"`
+	BasicAuth *BasicAuth  `json:"basic_auth,omitempty"`
+	OAuth2    *OAuth2Auth `json:"oauth2,omitempty"`
 }
 
 type ApiKeyAuth struct {
@@ -101,6 +155,7 @@ type EndpointParams struct {
 }
 
 type RollSecretRequest struct {
+	// Expiration is how many hours the current secret stays valid.
 	Expiration int    `json:"expiration"`
 	Secret     string `json:"secret"`
 }
@@ -200,18 +255,75 @@ func (e *Endpoint) Pause(ctx context.Context, Id string) (*EndpointResponse, err
 	return respPtr, nil
 }
 
+// Activate resumes deliveries to a paused or inactive endpoint.
+func (e *Endpoint) Activate(ctx context.Context, Id string) (*EndpointResponse, error) {
+	url, err := addOptions(e.generateUrl()+"/"+Id+"/activate", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	respPtr := &EndpointResponse{}
+	err = postJSON(ctx, e.client, url, struct{}{}, respPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return respPtr, nil
+}
+
+// Health fetches the endpoint along with its DefaultHealthFailureLimit most
+// recent failed deliveries.
+func (e *Endpoint) Health(ctx context.Context, Id string) (*EndpointHealth, error) {
+	endpoint, err := e.Find(ctx, Id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	failures, err := e.client.EventDeliveries.All(ctx, &EventDeliveryParams{
+		ListParams: ListParams{PerPage: DefaultHealthFailureLimit},
+		EndpointID: []string{Id},
+		Status:     []string{"Failure"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	health := &EndpointHealth{
+		EndpointID:     endpoint.UID,
+		Status:         endpoint.Status,
+		FailureRate:    endpoint.FailureRate,
+		RecentFailures: failures.Content,
+	}
+
+	if endpoint.CBState != nil {
+		health.CBState = *endpoint.CBState
+	}
+
+	return health, nil
+}
+
+// RollSecret replaces the endpoint's secret; the current one stays valid for
+// body.Expiration hours. See SecretRotator for a coordinated rotation.
 func (e Endpoint) RollSecret(ctx context.Context, Id string, body *RollSecretRequest) error {
+	_, err := e.ExpireSecret(ctx, Id, body)
+	return err
+}
+
+// ExpireSecret is RollSecret returning the updated endpoint, whose Secrets
+// hold the new secret and the old ones until they expire.
+func (e *Endpoint) ExpireSecret(ctx context.Context, Id string, body *RollSecretRequest) (*EndpointResponse, error) {
 	url, err := addOptions(e.generateUrl()+"/"+Id+"/expire_secret", nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	respPtr := &EndpointResponse{}
 	err = putResource(ctx, e.client, url, body, respPtr)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	return respPtr, nil
 }
 
 func (e *Endpoint) generateUrl() string {
@@ -0,0 +1,266 @@
+package convoy_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+var (
+	ErrInvalidSigningKey  = errors.New("invalid signing key")
+	ErrInvalidClientCert  = errors.New("invalid mTLS client certificate")
+	ErrOAuth2TestFailed   = errors.New("oauth2 connection test failed")
+	ErrUnsupportedKeyType = errors.New("unsupported signing key type")
+)
+
+// Endpoint authentication types accepted in EndpointAuth.Type.
+const (
+	EndpointAuthAPIKey    = "api_key"
+	EndpointAuthBasicAuth = "basic_auth"
+	EndpointAuthOAuth2    = "oauth2"
+)
+
+// OAuth2AuthType is how Convoy authenticates to the token endpoint.
+type OAuth2AuthType string
+
+const (
+	// OAuth2SharedSecret sends ClientID and ClientSecret.
+	OAuth2SharedSecret OAuth2AuthType = "shared_secret"
+	// OAuth2ClientAssertion sends a JWT signed with SigningKey.
+	OAuth2ClientAssertion OAuth2AuthType = "client_assertion"
+)
+
+// OAuth2Auth makes Convoy fetch a client-credentials token from URL and
+// send it with every delivery.
+type OAuth2Auth struct {
+	URL                string         `json:"url"`
+	ClientID           string         `json:"client_id"`
+	AuthenticationType OAuth2AuthType `json:"authentication_type,omitempty"`
+	GrantType          string         `json:"grant_type,omitempty"`
+	Scope              string         `json:"scope,omitempty"`
+	Audience           string         `json:"audience,omitempty"`
+
+	// ClientSecret is used with OAuth2SharedSecret.
+	ClientSecret string `json:"client_secret,omitempty"`
+
+	// Issuer, Subject, SigningAlgorithm and SigningKey build the JWT
+	// assertion used with OAuth2ClientAssertion.
+	Issuer           string `json:"issuer,omitempty"`
+	Subject          string `json:"subject,omitempty"`
+	SigningAlgorithm string `json:"signing_algorithm,omitempty"`
+	SigningKey       *JWK   `json:"signing_key,omitempty"`
+
+	// ExpiryTimeUnit is the unit of the token response's expiry field:
+	// "seconds", "milliseconds", "minutes" or "hours".
+	ExpiryTimeUnit string              `json:"expiry_time_unit,omitempty"`
+	FieldMapping   *OAuth2FieldMapping `json:"field_mapping,omitempty"`
+}
+
+// OAuth2FieldMapping names the token response fields when the token
+// endpoint doesn't use the standard ones.
+type OAuth2FieldMapping struct {
+	AccessToken string `json:"access_token,omitempty"`
+	TokenType   string `json:"token_type,omitempty"`
+	ExpiresIn   string `json:"expires_in,omitempty"`
+}
+
+// JWK is an EC or RSA private key in JSON Web Key form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+
+	// EC fields.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	// D is the EC private key or the RSA private exponent.
+	D string `json:"d,omitempty"`
+
+	// RSA fields.
+	N  string `json:"n,omitempty"`
+	E  string `json:"e,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	Dp string `json:"dp,omitempty"`
+	Dq string `json:"dq,omitempty"`
+	Qi string `json:"qi,omitempty"`
+}
+
+// MtlsClientCert is the client certificate Convoy presents to the endpoint.
+// Convoy doesn't return ClientKey in responses.
+type MtlsClientCert struct {
+	ClientCert string `json:"client_cert"`
+	ClientKey  string `json:"client_key,omitempty"`
+}
+
+type OAuth2TestResponse struct {
+	Success     bool   `json:"success"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	// ExpiresAt is passed through as the token endpoint reported it, and is
+	// often empty.
+	ExpiresAt string `json:"expires_at"`
+	Message   string `json:"message"`
+	Error     string `json:"error"`
+}
+
+type testOAuth2Request struct {
+	OAuth2 *OAuth2Auth `json:"oauth2"`
+}
+
+// LoadMtlsClientCert reads a PEM certificate and private key and checks
+// that they form a valid pair.
+func LoadMtlsClientCert(certFile, keyFile string) (*MtlsClientCert, error) {
+	cert, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidClientCert, err)
+	}
+
+	return &MtlsClientCert{
+		ClientCert: string(cert),
+		ClientKey:  string(key),
+	}, nil
+}
+
+// LoadJWK reads a signing key from path, either as a JWK JSON document or
+// as a PEM-encoded EC or RSA private key.
+func LoadJWK(path string) (*JWK, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		return jwkFromPEM(block)
+	}
+
+	jwk := &JWK{}
+	err = json.Unmarshal(data, jwk)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSigningKey, err)
+	}
+
+	switch {
+	case jwk.Kty != "EC" && jwk.Kty != "RSA":
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedKeyType, jwk.Kty)
+	case isStringEmpty(jwk.D):
+		return nil, fmt.Errorf("%w: not a private key", ErrInvalidSigningKey)
+	}
+
+	return jwk, nil
+}
+
+func jwkFromPEM(block *pem.Block) (*JWK, error) {
+	var key interface{}
+	var err error
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSigningKey, err)
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		var crv string
+		switch k.Curve {
+		case elliptic.P256():
+			crv = "P-256"
+		case elliptic.P384():
+			crv = "P-384"
+		case elliptic.P521():
+			crv = "P-521"
+		default:
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedKeyType, k.Curve.Params().Name)
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return &JWK{
+			Kty: "EC",
+			Crv: crv,
+			X:   encodeJWKInt(k.X, size),
+			Y:   encodeJWKInt(k.Y, size),
+			D:   encodeJWKInt(k.D, size),
+		}, nil
+	case *rsa.PrivateKey:
+		if len(k.Primes) != 2 {
+			return nil, fmt.Errorf("%w: multi-prime RSA", ErrUnsupportedKeyType)
+		}
+		k.Precompute()
+
+		return &JWK{
+			Kty: "RSA",
+			N:   encodeJWKInt(k.N, 0),
+			E:   encodeJWKInt(big.NewInt(int64(k.E)), 0),
+			D:   encodeJWKInt(k.D, 0),
+			P:   encodeJWKInt(k.Primes[0], 0),
+			Q:   encodeJWKInt(k.Primes[1], 0),
+			Dp:  encodeJWKInt(k.Precomputed.Dp, 0),
+			Dq:  encodeJWKInt(k.Precomputed.Dq, 0),
+			Qi:  encodeJWKInt(k.Precomputed.Qinv, 0),
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, key)
+	}
+}
+
+// encodeJWKInt base64url-encodes n big-endian, left-padded to size bytes.
+func encodeJWKInt(n *big.Int, size int) string {
+	b := n.Bytes()
+	if len(b) < size {
+		b = n.FillBytes(make([]byte, size))
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// TestOAuth2Connection asks Convoy to exchange the OAuth2 config for a token
+// without saving it. A config the token endpoint rejects is reported as
+// ErrOAuth2TestFailed along with the response.
+func (e *Endpoint) TestOAuth2Connection(ctx context.Context, body *OAuth2Auth) (*OAuth2TestResponse, error) {
+	url, err := addOptions(e.generateUrl()+"/oauth2/test", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	respPtr := &OAuth2TestResponse{}
+	err = postJSON(ctx, e.client, url, &testOAuth2Request{OAuth2: body}, respPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	if !respPtr.Success {
+		msg := strings.TrimSpace(respPtr.Error + " " + respPtr.Message)
+		return respPtr, fmt.Errorf("%w: %s", ErrOAuth2TestFailed, msg)
+	}
+
+	return respPtr, nil
+}
@@ -0,0 +1,112 @@
+package convoy_go
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+
+	return path
+}
+
+func TestLoadJWKConvertsPEMECKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := writePEM(t, t.TempDir(), "key.pem", "PRIVATE KEY", der)
+
+	jwk, err := LoadJWK(path)
+	require.NoError(t, err)
+	require.Equal(t, "EC", jwk.Kty)
+	require.Equal(t, "P-256", jwk.Crv)
+
+	d, err := base64.RawURLEncoding.DecodeString(jwk.D)
+	require.NoError(t, err)
+	require.Len(t, d, 32)
+	require.Zero(t, key.D.Cmp(new(big.Int).SetBytes(d)))
+}
+
+func TestLoadJWKRejectsPublicKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"kty":"EC","crv":"P-256","x":"AA","y":"AA"}`), 0o600))
+
+	_, err := LoadJWK(path)
+	require.ErrorIs(t, err, ErrInvalidSigningKey)
+}
+
+func TestLoadMtlsClientCertValidatesPair(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "convoy-client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := writePEM(t, dir, "cert.pem", "CERTIFICATE", certDER)
+	keyFile := writePEM(t, dir, "key.pem", "EC PRIVATE KEY", keyDER)
+
+	mtls, err := LoadMtlsClientCert(certFile, keyFile)
+	require.NoError(t, err)
+	require.Contains(t, mtls.ClientCert, "BEGIN CERTIFICATE")
+	require.Contains(t, mtls.ClientKey, "BEGIN EC PRIVATE KEY")
+
+	_, err = LoadMtlsClientCert(certFile, certFile)
+	require.ErrorIs(t, err, ErrInvalidClientCert)
+}
+
+func TestEndpointTestOAuth2ConnectionToleratesFreeFormExpiry(t *testing.T) {
+	for _, expiresAt := range []string{"", "3600", "2024-01-01 00:00:00 +0000 UTC"} {
+		c, _ := newTestClient(t, `{"status":true,"message":"ok","data":{"success":true,"access_token":"tok","token_type":"Bearer","expires_at":"`+expiresAt+`"}}`)
+
+		resp, err := c.Endpoints.TestOAuth2Connection(context.Background(), &OAuth2Auth{URL: "https://auth.example.com/token"})
+		require.NoError(t, err)
+		require.True(t, resp.Success)
+		require.Equal(t, expiresAt, resp.ExpiresAt)
+	}
+}
+
+func TestEndpointTestOAuth2Connection(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"ok","data":{"success":false,"error":"invalid_client"}}`)
+
+	resp, err := c.Endpoints.TestOAuth2Connection(context.Background(), &OAuth2Auth{
+		URL:                "https://auth.example.com/token",
+		ClientID:           "client-1",
+		ClientSecret:       "secret",
+		AuthenticationType: OAuth2SharedSecret,
+	})
+	require.ErrorIs(t, err, ErrOAuth2TestFailed)
+	require.Equal(t, "invalid_client", resp.Error)
+
+	require.Equal(t, http.MethodPost, captured.method)
+	require.Equal(t, "/projects/test-project-id/endpoints/oauth2/test", captured.path)
+	require.JSONEq(t, `{"oauth2":{"url":"https://auth.example.com/token","client_id":"client-1","client_secret":"secret","authentication_type":"shared_secret"}}`, captured.body)
+}
@@ -11,8 +11,23 @@ import (
 var (
 	ErrNotListEventResponse = errors.New("invalid list event response")
 	ErrNotEventResponse     = errors.New("invalid event response")
+	ErrBatchDeclined        = errors.New("batch operation declined")
 )
 
+// EventService is the events API. *Event implements it; convoymock has a
+// mock.
+type EventService interface {
+	All(ctx context.Context, query *EventParams) (*ListEventResponse, error)
+	Create(ctx context.Context, body *CreateEventRequest) (*EventResponse, error)
+	FanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) (*EventResponse, error)
+	BroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) (*BroadcastEventResponse, error)
+	CreateDynamic(ctx context.Context, body *CreateDynamicEventRequest) error
+	Find(ctx context.Context, eventID string) (*EventResponse, error)
+	Replay(ctx context.Context, eventID string) error
+	BatchReplay(ctx context.Context, query *BatchReplayOptions, opts ...BatchOption) error
+	CountAffected(ctx context.Context, query *BatchReplayOptions) (int, error)
+}
+
 type Event struct {
 	client *Client
 }
@@ -40,10 +55,20 @@ type CreateBroadcastEventRequest struct {
 	Data           json.RawMessage   `json:"data"`
 }
 
+// CreateDynamicEventRequest sends an event to an ad-hoc URL. Convoy finds or
+// creates the endpoint and subscription for the URL on the fly.
 type CreateDynamicEventRequest struct {
-	Endpoint     string `json:"endpoint"`
-	Subscription string `json:"subscription"`
-	Event        string `json:"event"`
+	// URL is the endpoint's URL; it must use https.
+	URL string `json:"url"`
+	// Secret is the endpoint's webhook secret. Convoy generates one for new
+	// endpoints when it's empty.
+	Secret string `json:"secret,omitempty"`
+	// EventTypes is the subscription's event type filter.
+	EventTypes     []string          `json:"event_types,omitempty"`
+	EventType      string            `json:"event_type"`
+	IdempotencyKey string            `json:"idempotency_key,omitempty"`
+	CustomHeaders  map[string]string `json:"custom_headers,omitempty"`
+	Data           json.RawMessage   `json:"data"`
 }
 
 type EventResponse struct {
@@ -51,10 +76,30 @@ type EventResponse struct {
 	EventType        string          `json:"event_type"`
 	MatchedEndpoints int             `json:"matched_endpoints"`
 	ProviderID       string          `json:"provider_id"`
+	Endpoints        []string        `json:"endpoints"`
+	IdempotencyKey   string          `json:"idempotency_key"`
+	IsDuplicateEvent bool            `json:"is_duplicate_event"`
+	Status           string          `json:"status"`
 	Data             json.RawMessage `json:"data"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Spooled reports that Convoy was unreachable and the event was appended
+	// to the client's spool for replay instead; the other fields are empty.
+	Spooled bool `json:"-"`
+}
+
+// BroadcastEventResponse reports whether Convoy accepted a broadcast event.
+// Broadcasts are matched to subscriptions asynchronously, so Event is only
+// set when the server returns the created event.
+type BroadcastEventResponse struct {
+	Accepted bool
+	Message  string
+	Event    *EventResponse
+	// Spooled reports that Convoy was unreachable and the event was appended
+	// to the client's spool for replay instead.
+	Spooled bool
 }
 
 type ListEventResponse struct {
@@ -78,6 +123,50 @@ type BatchReplayOptions struct {
 	EndDate   time.Time `url:"endDate" layout:"2006-01-02T15:04:05"`
 }
 
+// BatchOption configures BatchReplay and BatchResend.
+type BatchOption func(o *batchOptions)
+
+type batchOptions struct {
+	confirm func(count int) bool
+}
+
+// WithConfirm counts the events or deliveries a batch call would affect and
+// passes the count to confirm first. The call is aborted with
+// ErrBatchDeclined when confirm returns false. Deliveries are counted up to
+// DeliveryCountLimit, which then means at least that many.
+func WithConfirm(confirm func(count int) bool) BatchOption {
+	return func(o *batchOptions) {
+		o.confirm = confirm
+	}
+}
+
+// confirmBatch runs the WithConfirm callback, if any, against count.
+func confirmBatch(opts []BatchOption, count func() (int, error)) error {
+	o := &batchOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.confirm == nil {
+		return nil
+	}
+
+	n, err := count()
+	if err != nil {
+		return err
+	}
+
+	if !o.confirm(n) {
+		return ErrBatchDeclined
+	}
+
+	return nil
+}
+
+type countResponse struct {
+	Num int `json:"num"`
+}
+
 func newEvent(client *Client) *Event {
 	return &Event{
 		client: client,
@@ -99,46 +188,150 @@ func (e *Event) All(ctx context.Context, query *EventParams) (*ListEventResponse
 	return respPtr, nil
 }
 
-func (e *Event) Create(ctx context.Context, body *CreateEventRequest) error {
+// Create sends an event to a single endpoint and returns the event Convoy
+// created. When the client's dedupe window skips the call, the response from
+// the first send is returned; it is nil if that send went through a broker.
+// When Convoy is unreachable and the event was spooled, the response only has
+// Spooled set.
+func (e *Event) Create(ctx context.Context, body *CreateEventRequest) (*EventResponse, error) {
 	url, err := addOptions(e.generateUrl(), nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = postJSON(ctx, e.client, url, body, nil)
+	ev := *body
+	body = &ev
+
+	key, cached, duplicate := e.client.idempotency.prepare(messageTypeSingle, body)
+	if duplicate {
+		respPtr, _ := cached.(*EventResponse)
+		return respPtr, nil
+	}
+
+	err = e.client.limiter.waitEndpoint(ctx, body.EndpointID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	body.CustomHeaders = e.client.traceHeaders(ctx, body.CustomHeaders)
+
+	respPtr := &EventResponse{}
+	err = postJSON(ctx, e.client, url, body, respPtr, e.client.idempotency.header(key))
+	if err != nil {
+		if isUnreachable(err) && e.client.fallbackToSpool(ctx, "", messageTypeSingle, body, err) {
+			return &EventResponse{Spooled: true}, nil
+		}
+		return nil, err
+	}
+
+	e.client.idempotency.remember(key, respPtr)
+	return respPtr, nil
 }
 
-func (e *Event) FanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) error {
+// FanoutEvent sends an event to every endpoint of body.OwnerID and returns
+// the created event, whose Endpoints lists the endpoints it matched.
+func (e *Event) FanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) (*EventResponse, error) {
 	url, err := addOptions(e.generateUrl()+"/fanout", nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = postJSON(ctx, e.client, url, body, nil)
+	ev := *body
+	body = &ev
+
+	key, cached, duplicate := e.client.idempotency.prepare(messageTypeFanout, body)
+	if duplicate {
+		respPtr, _ := cached.(*EventResponse)
+		return respPtr, nil
+	}
+
+	body.CustomHeaders = e.client.traceHeaders(ctx, body.CustomHeaders)
+
+	respPtr := &EventResponse{}
+	err = postJSON(ctx, e.client, url, body, respPtr, e.client.idempotency.header(key))
 	if err != nil {
-		return err
+		if isUnreachable(err) && e.client.fallbackToSpool(ctx, "", messageTypeFanout, body, err) {
+			return &EventResponse{Spooled: true}, nil
+		}
+		return nil, err
 	}
 
-	return nil
+	e.client.idempotency.remember(key, respPtr)
+	return respPtr, nil
 }
 
-func (e *Event) BroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) error {
+// BroadcastEvent sends an event to every endpoint subscribed to its type.
+func (e *Event) BroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) (*BroadcastEventResponse, error) {
 	url, err := addOptions(e.generateUrl()+"/broadcast", nil)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	ev := *body
+	body = &ev
+
+	key, cached, duplicate := e.client.idempotency.prepare(messageTypeBroadcast, body)
+	if duplicate {
+		respPtr, _ := cached.(*BroadcastEventResponse)
+		return respPtr, nil
 	}
 
-	err = postJSON(ctx, e.client, url, body, nil)
+	body.CustomHeaders = e.client.traceHeaders(ctx, body.CustomHeaders)
+
+	envelope := &APIResponse{}
+	err = postJSON(ctx, e.client, url, body, envelope, e.client.idempotency.header(key))
+	if err != nil {
+		if isUnreachable(err) && e.client.fallbackToSpool(ctx, "", messageTypeBroadcast, body, err) {
+			return &BroadcastEventResponse{Spooled: true}, nil
+		}
+		return nil, err
+	}
+
+	respPtr := &BroadcastEventResponse{
+		Accepted: envelope.Status,
+		Message:  envelope.Message,
+	}
+
+	if envelope.Data != nil {
+		event := &EventResponse{}
+		if err := json.Unmarshal(*envelope.Data, event); err == nil && !isStringEmpty(event.UID) {
+			respPtr.Event = event
+		}
+	}
+
+	e.client.idempotency.remember(key, respPtr)
+	return respPtr, nil
+}
+
+// CreateDynamic delivers an event to body.URL without a pre-registered
+// endpoint. It returns nil when Convoy is unreachable and the event was
+// spooled.
+func (e *Event) CreateDynamic(ctx context.Context, body *CreateDynamicEventRequest) error {
+	url, err := addOptions(e.generateUrl()+"/dynamic", nil)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	ev := *body
+	body = &ev
+
+	key, _, duplicate := e.client.idempotency.prepare(messageTypeDynamic, body)
+	if duplicate {
+		return nil
+	}
+
+	body.CustomHeaders = e.client.traceHeaders(ctx, body.CustomHeaders)
+
+	err = postJSON(ctx, e.client, url, body, nil, e.client.idempotency.header(key))
+	if isUnreachable(err) && e.client.fallbackToSpool(ctx, "", messageTypeDynamic, body, err) {
+		return nil
+	}
+
+	if err == nil {
+		e.client.idempotency.remember(key, nil)
+	}
+
+	return err
 }
 
 func (e *Event) Find(ctx context.Context, eventID string) (*EventResponse, error) {
@@ -170,7 +363,14 @@ func (e *Event) Replay(ctx context.Context, eventID string) error {
 	return nil
 }
 
-func (e *Event) BatchReplay(ctx context.Context, query *BatchReplayOptions) error {
+func (e *Event) BatchReplay(ctx context.Context, query *BatchReplayOptions, opts ...BatchOption) error {
+	err := confirmBatch(opts, func() (int, error) {
+		return e.CountAffected(ctx, query)
+	})
+	if err != nil {
+		return err
+	}
+
 	url, err := addOptions(e.generateUrl()+"/batchreplay", query)
 	if err != nil {
 		return err
@@ -184,6 +384,22 @@ func (e *Event) BatchReplay(ctx context.Context, query *BatchReplayOptions) erro
 	return nil
 }
 
+// CountAffected returns how many events BatchReplay would replay for query.
+func (e *Event) CountAffected(ctx context.Context, query *BatchReplayOptions) (int, error) {
+	url, err := addOptions(e.generateUrl()+"/countbatchreplayevents", query)
+	if err != nil {
+		return 0, err
+	}
+
+	respPtr := &countResponse{}
+	err = getResource(ctx, e.client, url, respPtr)
+	if err != nil {
+		return 0, err
+	}
+
+	return respPtr.Num, nil
+}
+
 func (e *Event) generateUrl() string {
 	return fmt.Sprintf("%s/projects/%s/events", e.client.baseURL, e.client.projectID)
 }
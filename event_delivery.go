@@ -11,18 +11,45 @@ import (
 var (
 	ErrNotListEventDeliveryResponse = errors.New("invalid list event delivery response")
 	ErrNotEventDeliveryResponse     = errors.New("invalid event delivery response")
+	ErrForceResendFailed            = errors.New("delivery could not be queued for resend")
 )
 
+// DeliveryCountLimit caps EventDelivery.CountAffected, which pages through
+// deliveries: a count of DeliveryCountLimit means at least that many.
+const DeliveryCountLimit = 10000
+
+// deliveryCountPageSize is the page size EventDelivery.CountAffected uses
+// when the query doesn't set one.
+const deliveryCountPageSize = 100
+
+// DefaultForceResendChunkSize is how many IDs EventDelivery.ForceResend
+// sends per request, unless WithChunkSize says otherwise.
+const DefaultForceResendChunkSize = 50
+
+// EventDeliveryService is the event deliveries API. *EventDelivery
+// implements it; convoymock has a mock.
+type EventDeliveryService interface {
+	All(ctx context.Context, query *EventDeliveryParams) (*ListEventDeliveryResponse, error)
+	Find(ctx context.Context, eventDeliveryID string, query *EventDeliveryParams) (*EventDeliveryResponse, error)
+	Resend(ctx context.Context, eventDeliveryID string, query *EventDeliveryParams) (*EventDeliveryResponse, error)
+	BatchResend(ctx context.Context, query *EventDeliveryParams, opts ...BatchOption) error
+	ForceResend(ctx context.Context, ids []string, opts ...ForceResendOption) (*ForceResendResult, error)
+	CountAffected(ctx context.Context, query *EventDeliveryParams) (int, error)
+}
+
 type EventDelivery struct {
 	client *Client
 }
 
 type EventDeliveryResponse struct {
-	UID           string        `json:"uid"`
-	EventMetadata EventMetadata `json:"event_metadata"`
-	Metadata      Metadata      `json:"metadata"`
-	Description   string        `json:"description,omitempty"`
-	Status        string        `json:"status"`
+	UID            string        `json:"uid"`
+	EventID        string        `json:"event_id"`
+	EndpointID     string        `json:"endpoint_id"`
+	SubscriptionID string        `json:"subscription_id"`
+	EventMetadata  EventMetadata `json:"event_metadata"`
+	Metadata       Metadata      `json:"metadata"`
+	Description    string        `json:"description,omitempty"`
+	Status         string        `json:"status"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
@@ -66,6 +93,47 @@ type EventDeliveryParams struct {
 	EndDate        time.Time `url:"endDate" layout:"2006-01-02T15:04:05"`
 }
 
+// ForceResendResult aggregates the outcome of every chunk ForceResend sent.
+// Convoy reports how many deliveries in a chunk it queued, not which ones,
+// so outcomes are per chunk: Queued and Failed are totals, and Rejected lists
+// the IDs of chunks Convoy refused outright. With WithChunkSize(1) every ID
+// is its own chunk, and the IDs that failed are in Rejected too, with
+// ErrForceResendFailed.
+type ForceResendResult struct {
+	Queued   int
+	Failed   int
+	Rejected map[string]error
+	Chunks   []ForceResendChunk
+}
+
+type ForceResendChunk struct {
+	IDs    []string
+	Queued int
+	Failed int
+	Err    error
+}
+
+// ForceResendOption configures ForceResend.
+type ForceResendOption func(o *forceResendOptions)
+
+type forceResendOptions struct {
+	chunkSize int
+}
+
+// WithChunkSize sets how many IDs ForceResend sends per request. Sizes below
+// one are ignored.
+func WithChunkSize(n int) ForceResendOption {
+	return func(o *forceResendOptions) {
+		if n > 0 {
+			o.chunkSize = n
+		}
+	}
+}
+
+type forceResendRequest struct {
+	IDs []string `json:"ids"`
+}
+
 func newEventDelivery(client *Client) *EventDelivery {
 	return &EventDelivery{
 		client: client,
@@ -119,7 +187,14 @@ func (e *EventDelivery) Resend(ctx context.Context, eventDeliveryID string, quer
 
 // BatchResend retries deliveries matching the query filters; the server reads
 // filters from query params only.
-func (e *EventDelivery) BatchResend(ctx context.Context, query *EventDeliveryParams) error {
+func (e *EventDelivery) BatchResend(ctx context.Context, query *EventDeliveryParams, opts ...BatchOption) error {
+	err := confirmBatch(opts, func() (int, error) {
+		return e.CountAffected(ctx, query)
+	})
+	if err != nil {
+		return err
+	}
+
 	url, err := addOptions(e.generateUrl()+"/batchretry", query)
 	if err != nil {
 		return err
@@ -134,6 +209,105 @@ func (e *EventDelivery) BatchResend(ctx context.Context, query *EventDeliveryPar
 	return nil
 }
 
+// ForceResend redelivers the given deliveries even if they already
+// succeeded, DefaultForceResendChunkSize IDs per request. A refused chunk
+// doesn't stop the rest; it's recorded in the result. An error is returned
+// only if ctx ends, along with the result so far.
+func (e *EventDelivery) ForceResend(ctx context.Context, ids []string, opts ...ForceResendOption) (*ForceResendResult, error) {
+	url, err := addOptions(e.generateUrl()+"/forceresend", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &forceResendOptions{chunkSize: DefaultForceResendChunkSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	size := o.chunkSize
+
+	result := &ForceResendResult{Rejected: map[string]error{}}
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		chunk := ForceResendChunk{IDs: ids[start:end]}
+		if err := ctx.Err(); err != nil {
+			for _, id := range ids[start:] {
+				result.Rejected[id] = err
+			}
+			return result, err
+		}
+
+		envelope := &APIResponse{}
+		chunk.Err = postJSON(ctx, e.client, url, &forceResendRequest{IDs: chunk.IDs}, envelope)
+		if chunk.Err != nil {
+			for _, id := range chunk.IDs {
+				result.Rejected[id] = chunk.Err
+			}
+		} else {
+			chunk.Queued, chunk.Failed = parseForceResendMessage(envelope.Message, len(chunk.IDs))
+			if len(chunk.IDs) == 1 && chunk.Failed == 1 {
+				result.Rejected[chunk.IDs[0]] = ErrForceResendFailed
+			}
+		}
+
+		result.Queued += chunk.Queued
+		result.Failed += chunk.Failed
+		result.Chunks = append(result.Chunks, chunk)
+	}
+
+	return result, nil
+}
+
+// parseForceResendMessage reads Convoy's "N successful, M failed" message,
+// assuming the whole chunk was queued if the message has another shape.
+func parseForceResendMessage(msg string, size int) (queued, failed int) {
+	_, err := fmt.Sscanf(msg, "%d successful, %d failed", &queued, &failed)
+	if err != nil {
+		return size, 0
+	}
+
+	return queued, failed
+}
+
+// CountAffected returns how many deliveries BatchResend would retry for
+// query, up to DeliveryCountLimit. Convoy has no count route for deliveries,
+// so this pages through the matching deliveries and stops at the limit;
+// query's cursors are ignored.
+func (e *EventDelivery) CountAffected(ctx context.Context, query *EventDeliveryParams) (int, error) {
+	q := EventDeliveryParams{}
+	if query != nil {
+		q = *query
+	}
+
+	q.PrevPageCursor = ""
+	q.NextPageCursor = ""
+	if q.PerPage <= 0 {
+		q.PerPage = deliveryCountPageSize
+	}
+
+	count := 0
+	for {
+		resp, err := e.All(ctx, &q)
+		if err != nil {
+			return 0, err
+		}
+
+		count += len(resp.Content)
+		if count >= DeliveryCountLimit {
+			return DeliveryCountLimit, nil
+		}
+
+		if !resp.Pagination.HasNextPage || isStringEmpty(resp.Pagination.NextPageCursor) {
+			return count, nil
+		}
+
+		q.NextPageCursor = resp.Pagination.NextPageCursor
+	}
+}
+
 func (e *EventDelivery) generateUrl() string {
 	return fmt.Sprintf("%s/projects/%s/eventdeliveries", e.client.baseURL, e.client.projectID)
 }
@@ -52,7 +52,7 @@ func main() {
 }
 
 func createEvent(ctx context.Context, endpointID string, c *convoy.Client) {
-	err := c.Events.Create(ctx, &convoy.CreateEventRequest{
+	event, err := c.Events.Create(ctx, &convoy.CreateEventRequest{
 		EndpointID: endpointID,
 		EventType:  "test.event",
 		Data:       []byte(`{"event_type": "test.event", "data": { "version": "Convoy Cloud" }}`),
@@ -63,8 +63,8 @@ func createEvent(ctx context.Context, endpointID string, c *convoy.Client) {
 		return
 	}
 
-	//log.Printf("\nEndpoint event created - %+v\n", event)
-	//log.Printf("\nEndpoint event data - %+v\n", string(event.Data))
+	log.Printf("\nEndpoint event created - %+v\n", event)
+	log.Printf("\nEndpoint event data - %+v\n", string(event.Data))
 }
 
 func createEndpoint(ctx context.Context, c *convoy.Client) string {
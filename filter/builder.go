@@ -0,0 +1,353 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+)
+
+// Target is the part of a request a condition looks at.
+type Target string
+
+const (
+	TargetBody   Target = "body"
+	TargetHeader Target = "header"
+	TargetPath   Target = "path"
+	TargetQuery  Target = "query"
+)
+
+// Field is a field of a target that conditions are built on, e.g.
+// Body("data.amount").Gte(100).
+type Field struct {
+	target Target
+	path   string
+}
+
+func Body(path string) *Field   { return &Field{target: TargetBody, path: path} }
+func Header(name string) *Field { return &Field{target: TargetHeader, path: name} }
+func Path(name string) *Field   { return &Field{target: TargetPath, path: name} }
+func Query(name string) *Field  { return &Field{target: TargetQuery, path: name} }
+
+func (f *Field) Eq(v interface{}) *Expr  { return f.cond("$eq", v) }
+func (f *Field) Neq(v interface{}) *Expr { return f.cond("$neq", v) }
+func (f *Field) Gt(v interface{}) *Expr  { return f.cond("$gt", v) }
+func (f *Field) Gte(v interface{}) *Expr { return f.cond("$gte", v) }
+func (f *Field) Lt(v interface{}) *Expr  { return f.cond("$lt", v) }
+func (f *Field) Lte(v interface{}) *Expr { return f.cond("$lte", v) }
+
+func (f *Field) In(vs ...interface{}) *Expr  { return f.cond("$in", vs) }
+func (f *Field) Nin(vs ...interface{}) *Expr { return f.cond("$nin", vs) }
+
+func (f *Field) Exists() *Expr    { return f.cond("$exist", true) }
+func (f *Field) NotExists() *Expr { return f.cond("$exist", false) }
+
+func (f *Field) Regex(pattern string) *Expr { return f.cond("$regex", pattern) }
+
+func (f *Field) cond(op string, arg interface{}) *Expr {
+	return &Expr{target: f.target, path: f.path, op: op, arg: arg}
+}
+
+// Expr is a condition or a group of conditions joined by $and or $or.
+type Expr struct {
+	// Set for conditions.
+	target Target
+	path   string
+	op     string
+	arg    interface{}
+
+	// Set for groups.
+	logic    string
+	operands []*Expr
+}
+
+// And joins every expression with $and.
+func And(exprs ...*Expr) *Expr { return &Expr{logic: "$and", operands: exprs} }
+
+// Or joins every expression with $or. All of them must be on one target,
+// since Convoy ands the body, header, path and query filters together.
+func Or(exprs ...*Expr) *Expr { return &Expr{logic: "$or", operands: exprs} }
+
+func (e *Expr) And(others ...*Expr) *Expr { return And(append([]*Expr{e}, others...)...) }
+func (e *Expr) Or(others ...*Expr) *Expr  { return Or(append([]*Expr{e}, others...)...) }
+
+// Documents are the filter documents an expression compiles to, one per
+// target. Nil documents match everything.
+type Documents struct {
+	Body    map[string]interface{}
+	Headers map[string]interface{}
+	Path    map[string]interface{}
+	Query   map[string]interface{}
+}
+
+// Request is the body of the subscription filter resource
+// (/subscriptions/{id}/filters).
+type Request struct {
+	EventType string                 `json:"event_type"`
+	Body      map[string]interface{} `json:"body,omitempty"`
+	Headers   map[string]interface{} `json:"headers,omitempty"`
+	Path      map[string]interface{} `json:"path,omitempty"`
+	Query     map[string]interface{} `json:"query,omitempty"`
+}
+
+// Validate checks operator arguments and that $or groups stay on one target.
+func (e *Expr) Validate() error {
+	if e == nil {
+		return fmt.Errorf("%w: nil expression", ErrInvalidFilter)
+	}
+
+	if e.logic == "" {
+		return e.validateCondition()
+	}
+
+	if len(e.operands) == 0 {
+		return fmt.Errorf("%w: %s needs at least one operand", ErrInvalidFilter, e.logic)
+	}
+
+	for _, o := range e.operands {
+		err := o.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.logic == "$or" && len(e.targets()) > 1 {
+		return fmt.Errorf("%w: $or can't span %s", ErrInvalidFilter, strings.Join(e.targetNames(), " and "))
+	}
+
+	return nil
+}
+
+func (e *Expr) validateCondition() error {
+	if strings.TrimSpace(e.path) == "" {
+		return fmt.Errorf("%w: %s condition has no field", ErrInvalidFilter, e.target)
+	}
+
+	switch e.op {
+	case "$gt", "$gte", "$lt", "$lte":
+		if _, ok := toFloat(e.arg); !ok {
+			return fmt.Errorf("%w: %s %s takes a number, got %T", ErrInvalidFilter, e.path, e.op, e.arg)
+		}
+	case "$in", "$nin":
+		if l, _ := toList(e.arg); len(l) == 0 {
+			return fmt.Errorf("%w: %s %s takes at least one value", ErrInvalidFilter, e.path, e.op)
+		}
+	case "$regex":
+		if _, err := regexp.Compile(e.arg.(string)); err != nil {
+			return fmt.Errorf("%w: %s $regex: %v", ErrInvalidFilter, e.path, err)
+		}
+	case "$eq", "$neq":
+		if _, err := json.Marshal(e.arg); err != nil {
+			return fmt.Errorf("%w: %s %s: %v", ErrInvalidFilter, e.path, e.op, err)
+		}
+	}
+
+	return nil
+}
+
+// Build compiles the expression into one filter document per target.
+func (e *Expr) Build() (*Documents, error) {
+	err := e.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	docs := &Documents{}
+	for _, t := range e.targetNames() {
+		doc := e.document(Target(t))
+		switch Target(t) {
+		case TargetBody:
+			docs.Body = doc
+		case TargetHeader:
+			docs.Headers = doc
+		case TargetPath:
+			docs.Path = doc
+		case TargetQuery:
+			docs.Query = doc
+		}
+	}
+
+	return docs, nil
+}
+
+// Config builds a subscription filter configuration for the event types.
+// Path and query conditions are only supported by the filter resource; use
+// Request for those.
+func (e *Expr) Config(eventTypes ...string) (*convoy.FilterConfiguration, error) {
+	docs, err := e.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	if docs.Path != nil || docs.Query != nil {
+		return nil, fmt.Errorf("%w: path and query conditions need the filter resource", ErrInvalidFilter)
+	}
+
+	return &convoy.FilterConfiguration{
+		EventTypes: eventTypes,
+		Filter:     convoy.Filter{Body: docs.Body, Headers: docs.Headers},
+	}, nil
+}
+
+// Request builds a filter resource request for eventType.
+func (e *Expr) Request(eventType string) (*Request, error) {
+	docs, err := e.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Request{
+		EventType: eventType,
+		Body:      docs.Body,
+		Headers:   docs.Headers,
+		Path:      docs.Path,
+		Query:     docs.Query,
+	}, nil
+}
+
+// String renders the expression for logs, e.g.
+// `body.data.amount >= 100 AND header.x-tenant IN ["a","b"]`.
+func (e *Expr) String() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	if e.logic == "" {
+		arg, _ := json.Marshal(e.arg)
+		switch e.op {
+		case "$exist":
+			if e.arg == true {
+				return fmt.Sprintf("%s.%s EXISTS", e.target, e.path)
+			}
+			return fmt.Sprintf("%s.%s NOT EXISTS", e.target, e.path)
+		default:
+			return fmt.Sprintf("%s.%s %s %s", e.target, e.path, opSymbols[e.op], arg)
+		}
+	}
+
+	parts := make([]string, len(e.operands))
+	for i, o := range e.operands {
+		parts[i] = o.String()
+		if o != nil && o.logic != "" && o.logic != e.logic {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+
+	return strings.Join(parts, " "+strings.ToUpper(strings.TrimPrefix(e.logic, "$"))+" ")
+}
+
+var opSymbols = map[string]string{
+	"$eq":    "=",
+	"$neq":   "!=",
+	"$gt":    ">",
+	"$gte":   ">=",
+	"$lt":    "<",
+	"$lte":   "<=",
+	"$in":    "IN",
+	"$nin":   "NOT IN",
+	"$regex": "=~",
+}
+
+// document compiles the conditions on target t. Conditions are merged into
+// one object while their fields don't clash, and wrapped in $and otherwise.
+func (e *Expr) document(t Target) map[string]interface{} {
+	if e.logic == "" {
+		if e.target != t {
+			return nil
+		}
+		return map[string]interface{}{e.path: map[string]interface{}{e.op: e.arg}}
+	}
+
+	var docs []map[string]interface{}
+	for _, o := range e.operands {
+		if doc := o.document(t); doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+
+	switch {
+	case len(docs) == 0:
+		return nil
+	case len(docs) == 1:
+		return docs[0]
+	case e.logic == "$or":
+		return map[string]interface{}{"$or": docs}
+	}
+
+	if merged, ok := mergeDocuments(docs); ok {
+		return merged
+	}
+
+	return map[string]interface{}{"$and": docs}
+}
+
+// mergeDocuments merges and-ed documents whose keys don't overlap, except
+// for operator objects on the same field with different operators.
+func mergeDocuments(docs []map[string]interface{}) (map[string]interface{}, bool) {
+	merged := map[string]interface{}{}
+	for _, doc := range docs {
+		for k, v := range doc {
+			existing, clash := merged[k]
+			if !clash {
+				merged[k] = v
+				continue
+			}
+
+			a, aok := existing.(map[string]interface{})
+			b, bok := v.(map[string]interface{})
+			if !aok || !bok || strings.HasPrefix(k, "$") {
+				return nil, false
+			}
+
+			ops := make(map[string]interface{}, len(a)+len(b))
+			for op, arg := range a {
+				ops[op] = arg
+			}
+			for op, arg := range b {
+				if _, dup := ops[op]; dup || !strings.HasPrefix(op, "$") {
+					return nil, false
+				}
+				ops[op] = arg
+			}
+			merged[k] = ops
+		}
+	}
+
+	return merged, true
+}
+
+func (e *Expr) targets() map[Target]bool {
+	out := map[Target]bool{}
+	if e == nil {
+		return out
+	}
+
+	if e.logic == "" {
+		out[e.target] = true
+		return out
+	}
+
+	for _, o := range e.operands {
+		for t := range o.targets() {
+			out[t] = true
+		}
+	}
+
+	return out
+}
+
+// targetNames lists the expression's targets in a fixed order.
+func (e *Expr) targetNames() []string {
+	ts := e.targets()
+
+	var names []string
+	for _, t := range []Target{TargetBody, TargetHeader, TargetPath, TargetQuery} {
+		if ts[t] {
+			names = append(names, string(t))
+		}
+	}
+
+	return names
+}
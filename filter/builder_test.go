@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderEmitsBodyAndHeaderDocuments(t *testing.T) {
+	expr := Body("data.amount").Gte(100).And(Header("x-tenant").In("a", "b"))
+
+	cfg, err := expr.Config("invoice.paid")
+	require.NoError(t, err)
+	require.Equal(t, []string{"invoice.paid"}, cfg.EventTypes)
+
+	body, err := json.Marshal(cfg.Filter.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data.amount":{"$gte":100}}`, string(body))
+
+	headers, err := json.Marshal(cfg.Filter.Headers)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"x-tenant":{"$in":["a","b"]}}`, string(headers))
+
+	require.Equal(t, `body.data.amount >= 100 AND header.x-tenant IN ["a","b"]`, expr.String())
+}
+
+func TestBuilderMergesAndWrapsConditions(t *testing.T) {
+	expr := And(
+		Body("data.amount").Gt(10),
+		Body("data.amount").Lt(20),
+		Or(Body("data.currency").Eq("NGN"), Body("data.currency").Eq("GHS")),
+	)
+
+	docs, err := expr.Build()
+	require.NoError(t, err)
+
+	body, err := json.Marshal(docs.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"data.amount": {"$gt": 10, "$lt": 20},
+		"$or": [{"data.currency": {"$eq": "NGN"}}, {"data.currency": {"$eq": "GHS"}}]
+	}`, string(body))
+
+	require.Equal(t, `body.data.amount > 10 AND body.data.amount < 20 AND (body.data.currency = "NGN" OR body.data.currency = "GHS")`, expr.String())
+}
+
+func TestBuilderOutputMatchesEvaluator(t *testing.T) {
+	expr := Body("data.lines.sku").In("a-1", "z-9").
+		And(Body("data.refund").NotExists(), Header("X-Tenant").Regex("^ac"))
+
+	cfg, err := expr.Config("*")
+	require.NoError(t, err)
+
+	ok, err := MatchConfig(cfg, "invoice.paid", []byte(testPayload), http.Header{"X-Tenant": []string{"acme"}})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = MatchConfig(cfg, "invoice.paid", []byte(testPayload), http.Header{"X-Tenant": []string{"globex"}})
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestBuilderRequestCoversPathAndQuery(t *testing.T) {
+	expr := Path("tenant").Eq("acme").And(Query("version").In("v1", "v2"))
+
+	_, err := expr.Config()
+	require.ErrorIs(t, err, ErrInvalidFilter)
+
+	req, err := expr.Request("invoice.paid")
+	require.NoError(t, err)
+
+	b, err := json.Marshal(req)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"event_type": "invoice.paid",
+		"path": {"tenant": {"$eq": "acme"}},
+		"query": {"version": {"$in": ["v1", "v2"]}}
+	}`, string(b))
+}
+
+func TestBuilderValidatesOperators(t *testing.T) {
+	tests := []struct {
+		name string
+		expr *Expr
+	}{
+		{"non-numeric comparison", Body("data.amount").Gte("100")},
+		{"empty in", Body("data.currency").In()},
+		{"bad regex", Body("event").Regex("(")},
+		{"empty field", Body("").Eq(1)},
+		{"empty group", Or()},
+		{"or across targets", Body("data.amount").Eq(1).Or(Header("x-tenant").Eq("a"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.expr.Build()
+			require.ErrorIs(t, err, ErrInvalidFilter)
+		})
+	}
+}
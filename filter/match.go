@@ -0,0 +1,438 @@
+// Package filter evaluates and builds Convoy subscription filters offline.
+//
+// Filter documents use Convoy's operator syntax. Keys are field names,
+// nested either as objects or as dotted paths ("data.amount"); a numeric
+// path segment indexes an array, and any other segment applied to an array
+// is applied to each of its elements. A field's value is either a literal,
+// compared for equality, or an object of operators:
+//
+//	$eq, $neq         equality
+//	$gt, $gte, $lt, $lte  numeric comparison
+//	$in, $nin         membership in a list
+//	$exist            whether the field is present
+//	$regex            regular expression match on strings
+//
+// $or and $and take a list of filter documents, at the top level or under a
+// field. Sibling keys are and-ed. When a field's value is an array, a literal
+// or operator matches if any element matches, and $neq and $nin match only
+// if no element does.
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+)
+
+var (
+	ErrUnknownOperator = errors.New("unknown filter operator")
+	ErrInvalidFilter   = errors.New("invalid filter")
+)
+
+// Match reports whether payload matches the body filter document. An empty
+// filter matches every payload.
+func Match(filter map[string]interface{}, payload json.RawMessage) (bool, error) {
+	if len(filter) == 0 {
+		return true, nil
+	}
+
+	var doc interface{}
+	if len(bytes.TrimSpace(payload)) > 0 {
+		err := json.Unmarshal(payload, &doc)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return evalNode(filter, "", func(path string) (interface{}, bool) {
+		return lookup(doc, path)
+	})
+}
+
+// MatchHeaders reports whether headers match the header filter document.
+// Header names are matched case-insensitively, and every header is treated
+// as a list of its values.
+func MatchHeaders(filter map[string]interface{}, headers http.Header) (bool, error) {
+	if len(filter) == 0 {
+		return true, nil
+	}
+
+	values := make(map[string][]interface{}, len(headers))
+	for name, vs := range headers {
+		key := strings.ToLower(name)
+		for _, v := range vs {
+			values[key] = append(values[key], v)
+		}
+	}
+
+	return evalNode(filter, "", func(path string) (interface{}, bool) {
+		v, ok := values[strings.ToLower(path)]
+		return v, ok
+	})
+}
+
+// MatchConfig reports whether an event would pass a subscription's filter
+// configuration: its event type, body filter and header filter.
+func MatchConfig(cfg *convoy.FilterConfiguration, eventType string, payload json.RawMessage, headers http.Header) (bool, error) {
+	if cfg == nil {
+		return true, nil
+	}
+
+	if !matchEventType(cfg.EventTypes, eventType) {
+		return false, nil
+	}
+
+	ok, err := Match(cfg.Filter.Body, payload)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return MatchHeaders(cfg.Filter.Headers, headers)
+}
+
+// Subscriptions returns the subscriptions an event would be delivered to.
+func Subscriptions(subs []convoy.SubscriptionResponse, eventType string, payload json.RawMessage, headers http.Header) ([]convoy.SubscriptionResponse, error) {
+	var matched []convoy.SubscriptionResponse
+	for _, sub := range subs {
+		ok, err := MatchConfig(sub.FilterConfig, eventType, payload, headers)
+		if err != nil {
+			return nil, fmt.Errorf("subscription %s: %w", sub.UID, err)
+		}
+
+		if ok {
+			matched = append(matched, sub)
+		}
+	}
+
+	return matched, nil
+}
+
+// matchEventType matches an event type against a subscription's list,
+// where "*" or an empty list matches every type.
+func matchEventType(eventTypes []string, eventType string) bool {
+	if len(eventTypes) == 0 {
+		return true
+	}
+
+	for _, et := range eventTypes {
+		if et == "*" || et == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type lookupFunc func(path string) (interface{}, bool)
+
+// evalNode evaluates a filter node found at path. Object nodes can mix
+// nested fields, operators on path and $or/$and; all of them must match.
+func evalNode(node interface{}, path string, get lookupFunc) (bool, error) {
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		if path == "" {
+			return false, fmt.Errorf("%w: filter must be an object", ErrInvalidFilter)
+		}
+
+		v, found := get(path)
+		return found && equals(v, node), nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var matched bool
+		var err error
+
+		switch {
+		case key == "$or" || key == "$and":
+			matched, err = evalLogical(key, m[key], path, get)
+		case strings.HasPrefix(key, "$"):
+			if path == "" {
+				return false, fmt.Errorf("%w: operator %s has no field", ErrInvalidFilter, key)
+			}
+			matched, err = evalOperator(key, m[key], path, get)
+		default:
+			matched, err = evalNode(m[key], joinPath(path, key), get)
+		}
+
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func evalLogical(op string, arg interface{}, path string, get lookupFunc) (bool, error) {
+	docs, ok := toList(arg)
+	if !ok || len(docs) == 0 {
+		return false, fmt.Errorf("%w: %s takes a non-empty list of filters", ErrInvalidFilter, op)
+	}
+
+	for _, doc := range docs {
+		if _, ok := doc.(map[string]interface{}); !ok {
+			return false, fmt.Errorf("%w: %s takes a list of filters", ErrInvalidFilter, op)
+		}
+
+		matched, err := evalNode(doc, path, get)
+		if err != nil {
+			return false, err
+		}
+
+		if op == "$or" && matched {
+			return true, nil
+		}
+
+		if op == "$and" && !matched {
+			return false, nil
+		}
+	}
+
+	return op == "$and", nil
+}
+
+func evalOperator(op string, arg interface{}, path string, get lookupFunc) (bool, error) {
+	v, found := get(path)
+
+	switch op {
+	case "$exist":
+		want, ok := arg.(bool)
+		if !ok {
+			return false, fmt.Errorf("%w: $exist takes a boolean", ErrInvalidFilter)
+		}
+		return found == want, nil
+	case "$eq":
+		return found && equals(v, arg), nil
+	case "$neq":
+		return !found || !equals(v, arg), nil
+	case "$in", "$nin":
+		list, ok := toList(arg)
+		if !ok {
+			return false, fmt.Errorf("%w: %s takes a list", ErrInvalidFilter, op)
+		}
+
+		in := false
+		for _, item := range list {
+			if found && equals(v, item) {
+				in = true
+				break
+			}
+		}
+		return in == (op == "$in"), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		want, ok := toFloat(arg)
+		if !ok {
+			return false, fmt.Errorf("%w: %s takes a number", ErrInvalidFilter, op)
+		}
+		return found && anyValue(v, func(x interface{}) bool {
+			got, ok := toFloat(x)
+			return ok && compareFloats(op, got, want)
+		}), nil
+	case "$regex":
+		pattern, ok := arg.(string)
+		if !ok {
+			return false, fmt.Errorf("%w: $regex takes a string", ErrInvalidFilter)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		return found && anyValue(v, func(x interface{}) bool {
+			s, ok := x.(string)
+			return ok && re.MatchString(s)
+		}), nil
+	default:
+		return false, fmt.Errorf("%w: %s", ErrUnknownOperator, op)
+	}
+}
+
+func compareFloats(op string, got, want float64) bool {
+	switch op {
+	case "$gt":
+		return got > want
+	case "$gte":
+		return got >= want
+	case "$lt":
+		return got < want
+	default:
+		return got <= want
+	}
+}
+
+// equals matches a payload value against a filter literal. An array value
+// matches if it equals the literal or any of its elements does.
+func equals(v, want interface{}) bool {
+	if reflect.DeepEqual(normalize(v), normalize(want)) {
+		return true
+	}
+
+	arr, ok := v.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, x := range arr {
+		if equals(x, want) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// anyValue applies fn to v, or to each element if v is an array.
+func anyValue(v interface{}, fn func(interface{}) bool) bool {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return fn(v)
+	}
+
+	for _, x := range arr {
+		if anyValue(x, fn) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalize makes values decoded from JSON and literals written in Go
+// comparable, converting every number to float64.
+func normalize(v interface{}) interface{} {
+	if f, ok := toFloat(v); ok {
+		return f
+	}
+
+	if l, ok := toList(v); ok {
+		out := make([]interface{}, len(l))
+		for i, x := range l {
+			out[i] = normalize(x)
+		}
+		return out
+	}
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, x := range t {
+			out[k] = normalize(x)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// toList accepts any slice, so filters written in Go can use []string or
+// []map[string]interface{} as well as []interface{}.
+func toList(v interface{}) ([]interface{}, bool) {
+	if l, ok := v.([]interface{}); ok {
+		return l, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+
+	return out, true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// lookup resolves a dotted path in a decoded JSON document.
+func lookup(doc interface{}, path string) (interface{}, bool) {
+	cur := doc
+	for _, seg := range strings.Split(path, ".") {
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			v, ok := t[seg]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []interface{}:
+			if i, err := strconv.Atoi(seg); err == nil {
+				if i < 0 || i >= len(t) {
+					return nil, false
+				}
+				cur = t[i]
+				continue
+			}
+
+			var out []interface{}
+			for _, x := range t {
+				if v, ok := lookup(x, seg); ok {
+					out = append(out, v)
+				}
+			}
+			if len(out) == 0 {
+				return nil, false
+			}
+			cur = out
+		default:
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+func joinPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
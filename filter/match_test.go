@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"net/http"
+	"testing"
+
+	convoy "github.com/frain-dev/convoy-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const testPayload = `{
+	"event": "invoice.paid",
+	"data": {
+		"amount": 150,
+		"currency": "NGN",
+		"tags": ["vip", "annual"],
+		"lines": [{"sku": "a-1", "qty": 2}, {"sku": "b-2", "qty": 1}]
+	}
+}`
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter map[string]interface{}
+		want   bool
+	}{
+		{"empty filter", nil, true},
+		{"literal", map[string]interface{}{"event": "invoice.paid"}, true},
+		{"nested object", map[string]interface{}{"data": map[string]interface{}{"currency": "NGN"}}, true},
+		{"dotted path", map[string]interface{}{"data.amount": 150}, true},
+		{"literal mismatch", map[string]interface{}{"data.amount": 100}, false},
+		{"missing field", map[string]interface{}{"data.customer": "x"}, false},
+		{"gte", map[string]interface{}{"data.amount": map[string]interface{}{"$gte": 150}}, true},
+		{"gt", map[string]interface{}{"data.amount": map[string]interface{}{"$gt": 150}}, false},
+		{"range", map[string]interface{}{"data.amount": map[string]interface{}{"$gt": 100, "$lt": 200}}, true},
+		{"neq", map[string]interface{}{"data.currency": map[string]interface{}{"$neq": "USD"}}, true},
+		{"in", map[string]interface{}{"data.currency": map[string]interface{}{"$in": []interface{}{"NGN", "GHS"}}}, true},
+		{"nin", map[string]interface{}{"data.currency": map[string]interface{}{"$nin": []string{"NGN"}}}, false},
+		{"exist", map[string]interface{}{"data.tags": map[string]interface{}{"$exist": true}}, true},
+		{"not exist", map[string]interface{}{"data.refund": map[string]interface{}{"$exist": false}}, true},
+		{"regex", map[string]interface{}{"event": map[string]interface{}{"$regex": "^invoice\\."}}, true},
+		{"array contains", map[string]interface{}{"data.tags": "vip"}, true},
+		{"array neq", map[string]interface{}{"data.tags": map[string]interface{}{"$neq": "vip"}}, false},
+		{"array index", map[string]interface{}{"data.lines.1.sku": "b-2"}, true},
+		{"array fan-out", map[string]interface{}{"data.lines.qty": map[string]interface{}{"$gte": 2}}, true},
+		{"or", map[string]interface{}{"$or": []interface{}{
+			map[string]interface{}{"data.currency": "USD"},
+			map[string]interface{}{"data.amount": 150},
+		}}, true},
+		{"and", map[string]interface{}{"$and": []interface{}{
+			map[string]interface{}{"data.currency": "NGN"},
+			map[string]interface{}{"data.amount": map[string]interface{}{"$lt": 100}},
+		}}, false},
+		{"or under a field", map[string]interface{}{"data": map[string]interface{}{"$or": []interface{}{
+			map[string]interface{}{"currency": "USD"},
+			map[string]interface{}{"currency": "NGN"},
+		}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Match(tt.filter, []byte(testPayload))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatchRejectsInvalidFilters(t *testing.T) {
+	_, err := Match(map[string]interface{}{"data.amount": map[string]interface{}{"$between": 1}}, []byte(testPayload))
+	require.ErrorIs(t, err, ErrUnknownOperator)
+
+	_, err = Match(map[string]interface{}{"data.amount": map[string]interface{}{"$gte": "100"}}, []byte(testPayload))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+
+	_, err = Match(map[string]interface{}{"$eq": 1}, []byte(testPayload))
+	require.ErrorIs(t, err, ErrInvalidFilter)
+}
+
+func TestMatchHeadersIsCaseInsensitive(t *testing.T) {
+	headers := http.Header{"X-Tenant": []string{"acme"}}
+
+	ok, err := MatchHeaders(map[string]interface{}{"x-tenant": map[string]interface{}{"$in": []string{"acme", "globex"}}}, headers)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestSubscriptionsSelectsMatchingSubscriptions(t *testing.T) {
+	subs := []convoy.SubscriptionResponse{
+		{UID: "all", FilterConfig: &convoy.FilterConfiguration{EventTypes: []string{"*"}}},
+		{UID: "other-type", FilterConfig: &convoy.FilterConfiguration{EventTypes: []string{"invoice.created"}}},
+		{UID: "big", FilterConfig: &convoy.FilterConfiguration{
+			EventTypes: []string{"invoice.paid"},
+			Filter:     convoy.Filter{Body: map[string]interface{}{"data.amount": map[string]interface{}{"$gte": 100}}},
+		}},
+		{UID: "other-tenant", FilterConfig: &convoy.FilterConfiguration{
+			Filter: convoy.Filter{Headers: map[string]interface{}{"X-Tenant": "globex"}},
+		}},
+	}
+
+	matched, err := Subscriptions(subs, "invoice.paid", []byte(testPayload), http.Header{"X-Tenant": []string{"acme"}})
+	require.NoError(t, err)
+
+	var uids []string
+	for _, sub := range matched {
+		uids = append(uids, sub.UID)
+	}
+	require.Equal(t, []string{"all", "big"}, uids)
+}
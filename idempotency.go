@@ -0,0 +1,213 @@
+package convoy_go
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyStrategy decides how the client fills in an empty
+// IdempotencyKey on create event requests.
+type IdempotencyKeyStrategy int
+
+const (
+	// IdempotencyKeyNone sends keys exactly as the caller set them.
+	IdempotencyKeyNone IdempotencyKeyStrategy = iota
+
+	// IdempotencyKeyUUIDv7 assigns a new time-ordered UUID to every call.
+	// AsyncPublisher retries and spool replays reuse the key of the call
+	// they repeat; callers retrying themselves should set their own key.
+	IdempotencyKeyUUIDv7
+
+	// IdempotencyKeyHash derives the key from the target endpoint, owner or
+	// URL, the event type and the data, so identical events always share a key.
+	IdempotencyKeyHash
+)
+
+var DefaultRequestIDHeader = "X-Request-ID"
+
+type IdempotencyOptions struct {
+	Strategy IdempotencyKeyStrategy
+
+	// Header is the request-ID header the key is also sent in. Defaults to
+	// DefaultRequestIDHeader.
+	Header string
+
+	// DedupeWindow, when positive, makes the client remember every key it
+	// sent successfully for this long, and skip publishing an event whose
+	// key it has already sent.
+	DedupeWindow time.Duration
+}
+
+// OptionIdempotency enables automatic idempotency keys and client-side
+// dedupe for events created through the API, the brokers and the publishers.
+func OptionIdempotency(opts *IdempotencyOptions) func(c *Client) {
+	return func(c *Client) {
+		c.idempotency = newIdempotency(opts)
+	}
+}
+
+type idempotency struct {
+	opts *IdempotencyOptions
+
+	mu        sync.Mutex
+	seen      map[string]sentEvent
+	lastSweep time.Time
+}
+
+type sentEvent struct {
+	sentAt time.Time
+	// resp is what Convoy returned for the event; nil for broker writes.
+	resp interface{}
+}
+
+func newIdempotency(opts *IdempotencyOptions) *idempotency {
+	if opts == nil {
+		opts = &IdempotencyOptions{}
+	}
+
+	if isStringEmpty(opts.Header) {
+		opts.Header = DefaultRequestIDHeader
+	}
+
+	return &idempotency{
+		opts: opts,
+		seen: map[string]sentEvent{},
+	}
+}
+
+// prepare fills in body's idempotency key according to the strategy and
+// reports whether that key was already sent within the dedupe window, along
+// with the response remembered for it. body must be the client's own copy of
+// the caller's request. It is safe to call on a nil receiver.
+func (i *idempotency) prepare(msgType string, body interface{}) (key string, cached interface{}, duplicate bool) {
+	if i == nil {
+		return "", nil, false
+	}
+
+	key = i.assignKey(msgType, body)
+	if isStringEmpty(key) || i.opts.DedupeWindow <= 0 {
+		return key, nil, false
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	sent, ok := i.seen[key]
+	if !ok || time.Since(sent.sentAt) >= i.opts.DedupeWindow {
+		return key, nil, false
+	}
+
+	return key, sent.resp, true
+}
+
+// assignKey fills in body's idempotency key, when empty, according to the
+// strategy and returns it. body must be the client's own copy of the
+// caller's request. It is safe to call on a nil receiver.
+func (i *idempotency) assignKey(msgType string, body interface{}) string {
+	if i == nil {
+		return ""
+	}
+
+	var keyPtr *string
+	var target, eventType string
+	var data json.RawMessage
+
+	switch b := body.(type) {
+	case *CreateEventRequest:
+		keyPtr, target, eventType, data = &b.IdempotencyKey, b.EndpointID, b.EventType, b.Data
+	case *CreateFanoutEventRequest:
+		keyPtr, target, eventType, data = &b.IdempotencyKey, b.OwnerID, b.EventType, b.Data
+	case *CreateBroadcastEventRequest:
+		keyPtr, eventType, data = &b.IdempotencyKey, b.EventType, b.Data
+	case *CreateDynamicEventRequest:
+		keyPtr, target, eventType, data = &b.IdempotencyKey, b.URL, b.EventType, b.Data
+	default:
+		return ""
+	}
+
+	if isStringEmpty(*keyPtr) {
+		switch i.opts.Strategy {
+		case IdempotencyKeyUUIDv7:
+			id, err := uuid.NewV7()
+			if err != nil {
+				return ""
+			}
+			*keyPtr = id.String()
+		case IdempotencyKeyHash:
+			*keyPtr = hashIdempotencyKey(msgType, target, eventType, data)
+		}
+	}
+
+	return *keyPtr
+}
+
+// remember records key, and the response Convoy returned for it, as sent
+// for the dedupe window.
+func (i *idempotency) remember(key string, resp interface{}) {
+	if i == nil || isStringEmpty(key) || i.opts.DedupeWindow <= 0 {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := time.Now()
+	i.seen[key] = sentEvent{sentAt: now, resp: resp}
+
+	if now.Sub(i.lastSweep) < i.opts.DedupeWindow {
+		return
+	}
+
+	for k, sent := range i.seen {
+		if now.Sub(sent.sentAt) >= i.opts.DedupeWindow {
+			delete(i.seen, k)
+		}
+	}
+	i.lastSweep = now
+}
+
+// header returns a request option that sets the request-ID header to key.
+func (i *idempotency) header(key string) requestOption {
+	return func(req *http.Request) {
+		if i != nil && !isStringEmpty(key) {
+			req.Header.Set(i.opts.Header, key)
+		}
+	}
+}
+
+func hashIdempotencyKey(msgType, target, eventType string, data json.RawMessage) string {
+	h := sha256.New()
+	for _, part := range []string{msgType, target, eventType} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(canonicalJSON(data))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalJSON re-encodes data with sorted object keys and no insignificant
+// whitespace, so equal payloads hash equally however they were formatted.
+func canonicalJSON(data json.RawMessage) []byte {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return data
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+
+	return b
+}
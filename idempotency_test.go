@@ -0,0 +1,142 @@
+package convoy_go
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeyIsSentInBodyAndHeader(t *testing.T) {
+	var gotHeader, gotBody string
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotHeader = r.Header.Get(DefaultRequestIDHeader)
+		gotBody = string(body)
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}, OptionIdempotency(&IdempotencyOptions{Strategy: IdempotencyKeyUUIDv7}))
+
+	body := &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event", Data: []byte(`{}`)}
+	_, err := c.Events.Create(context.Background(), body)
+	require.NoError(t, err)
+
+	id, err := uuid.Parse(gotHeader)
+	require.NoError(t, err)
+	require.Equal(t, uuid.Version(7), id.Version())
+
+	var sent CreateEventRequest
+	require.NoError(t, json.Unmarshal([]byte(gotBody), &sent))
+	require.Equal(t, gotHeader, sent.IdempotencyKey)
+
+	// The key is the call's own; the caller's request is left as is.
+	require.Empty(t, body.IdempotencyKey)
+}
+
+func TestIdempotencyKeyFollowsReusedRequestData(t *testing.T) {
+	var keys []string
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(DefaultRequestIDHeader))
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}, OptionIdempotency(&IdempotencyOptions{Strategy: IdempotencyKeyHash}))
+
+	body := &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event", Data: []byte(`{"n":1}`)}
+	_, err := c.Events.Create(context.Background(), body)
+	require.NoError(t, err)
+
+	body.Data = []byte(`{"n":2}`)
+	_, err = c.Events.Create(context.Background(), body)
+	require.NoError(t, err)
+
+	require.Len(t, keys, 2)
+	require.NotEqual(t, keys[0], keys[1])
+	require.Equal(t, hashIdempotencyKey(messageTypeSingle, "ep-1", "test.event", []byte(`{"n":2}`)), keys[1])
+	require.Empty(t, body.IdempotencyKey)
+}
+
+func TestAsyncPublisherRetriesReuseTheIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(DefaultRequestIDHeader))
+		first := len(keys) == 1
+		mu.Unlock()
+
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":false,"message":"unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}, OptionIdempotency(&IdempotencyOptions{Strategy: IdempotencyKeyUUIDv7}))
+
+	p := newTestPublisher(t, c, &AsyncPublisherOptions{Workers: 1, RetryBackoff: time.Millisecond})
+	body := &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event", Data: []byte(`{}`)}
+	require.NoError(t, p.Publish(context.Background(), body))
+	require.NoError(t, p.Flush(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, keys, 2)
+	require.NotEmpty(t, keys[0])
+	require.Equal(t, keys[0], keys[1])
+	require.Empty(t, body.IdempotencyKey)
+}
+
+func TestIdempotencyCallerKeyIsKept(t *testing.T) {
+	i := newIdempotency(&IdempotencyOptions{Strategy: IdempotencyKeyHash})
+
+	body := &CreateFanoutEventRequest{OwnerID: "owner-1", EventType: "test.event", IdempotencyKey: "mine"}
+	key, _, _ := i.prepare(messageTypeFanout, body)
+	require.Equal(t, "mine", key)
+}
+
+func TestIdempotencyHashIgnoresJSONFormatting(t *testing.T) {
+	i := newIdempotency(&IdempotencyOptions{Strategy: IdempotencyKeyHash})
+
+	a := &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event", Data: []byte(`{"b":2,"a":1}`)}
+	b := &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event", Data: []byte(`{ "a": 1, "b": 2 }`)}
+	other := &CreateEventRequest{EndpointID: "ep-2", EventType: "test.event", Data: []byte(`{"a":1,"b":2}`)}
+
+	keyA, _, _ := i.prepare(messageTypeSingle, a)
+	keyB, _, _ := i.prepare(messageTypeSingle, b)
+	keyOther, _, _ := i.prepare(messageTypeSingle, other)
+
+	require.NotEmpty(t, keyA)
+	require.Equal(t, keyA, keyB)
+	require.NotEqual(t, keyA, keyOther)
+}
+
+func TestIdempotencyDedupeWindowShortCircuitsDuplicates(t *testing.T) {
+	var received atomic.Int64
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"evt-1"}}`))
+	}, OptionIdempotency(&IdempotencyOptions{Strategy: IdempotencyKeyHash, DedupeWindow: 50 * time.Millisecond}))
+
+	newBody := func() *CreateEventRequest {
+		return &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event", Data: []byte(`{"k":"v"}`)}
+	}
+
+	ctx := context.Background()
+	first, err := c.Events.Create(ctx, newBody())
+	require.NoError(t, err)
+
+	// The duplicate gets the first call's event back without a request.
+	dup, err := c.Events.Create(ctx, newBody())
+	require.NoError(t, err)
+	require.Same(t, first, dup)
+	require.Equal(t, int64(1), received.Load())
+
+	time.Sleep(60 * time.Millisecond)
+	_, err = c.Events.Create(ctx, newBody())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), received.Load())
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/segmentio/kafka-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 type KafkaOptions struct {
@@ -12,6 +13,14 @@ type KafkaOptions struct {
 	Topic  string
 }
 
+// BrokerService writes events to a message broker Convoy consumes from.
+// *Kafka and *SQS implement it; convoymock has a mock.
+type BrokerService interface {
+	WriteEvent(ctx context.Context, body *CreateEventRequest) error
+	WriteFanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) error
+	WriteBroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) error
+}
+
 type Kafka struct {
 	client *Client
 	writer *kafka.Writer
@@ -28,49 +37,125 @@ func newKafka(c *Client) *Kafka {
 	}
 }
 
-func (k *Kafka) WriteEvent(ctx context.Context, body *CreateEventRequest) error {
+func (k *Kafka) WriteEvent(ctx context.Context, body *CreateEventRequest) (err error) {
 	if body.CustomHeaders == nil {
 		body.CustomHeaders = map[string]string{"x-convoy-message-type": "single"}
 	} else {
 		body.CustomHeaders["x-convoy-message-type"] = "single"
 	}
 
+	ev := *body
+	body = &ev
+
+	key, _, duplicate := k.client.idempotency.prepare(messageTypeSingle, body)
+	if duplicate {
+		return nil
+	}
+
+	ctx, span := k.client.startPublishSpan(ctx, semconv.MessagingSystemKafka, k.writer.Topic, messageTypeSingle)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	body.CustomHeaders = k.client.traceHeaders(ctx, body.CustomHeaders)
+
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
 
-	return k.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+	err = k.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+	if err != nil {
+		k.client.metrics.IncBrokerError("kafka", messageTypeSingle)
+		if k.client.fallbackToSpool(ctx, spoolTransportKafka, messageTypeSingle, body, err) {
+			span.RecordError(err)
+			return nil
+		}
+		return err
+	}
 
+	k.client.idempotency.remember(key, nil)
+	return nil
 }
 
-func (k *Kafka) WriteFanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) error {
+func (k *Kafka) WriteFanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) (err error) {
 	if body.CustomHeaders == nil {
 		body.CustomHeaders = map[string]string{"x-convoy-message-type": "fanout"}
 	} else {
 		body.CustomHeaders["x-convoy-message-type"] = "fanout"
 	}
 
+	ev := *body
+	body = &ev
+
+	key, _, duplicate := k.client.idempotency.prepare(messageTypeFanout, body)
+	if duplicate {
+		return nil
+	}
+
+	ctx, span := k.client.startPublishSpan(ctx, semconv.MessagingSystemKafka, k.writer.Topic, messageTypeFanout)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	body.CustomHeaders = k.client.traceHeaders(ctx, body.CustomHeaders)
+
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
 
-	return k.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+	err = k.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+	if err != nil {
+		k.client.metrics.IncBrokerError("kafka", messageTypeFanout)
+		if k.client.fallbackToSpool(ctx, spoolTransportKafka, messageTypeFanout, body, err) {
+			span.RecordError(err)
+			return nil
+		}
+		return err
+	}
 
+	k.client.idempotency.remember(key, nil)
+	return nil
 }
 
-func (k *Kafka) WriteBroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) error {
+func (k *Kafka) WriteBroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) (err error) {
 	if body.CustomHeaders == nil {
 		body.CustomHeaders = map[string]string{"x-convoy-message-type": "broadcast"}
 	} else {
 		body.CustomHeaders["x-convoy-message-type"] = "broadcast"
 	}
 
+	ev := *body
+	body = &ev
+
+	key, _, duplicate := k.client.idempotency.prepare(messageTypeBroadcast, body)
+	if duplicate {
+		return nil
+	}
+
+	ctx, span := k.client.startPublishSpan(ctx, semconv.MessagingSystemKafka, k.writer.Topic, messageTypeBroadcast)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	body.CustomHeaders = k.client.traceHeaders(ctx, body.CustomHeaders)
+
 	payload, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
 
-	return k.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+	err = k.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+	if err != nil {
+		k.client.metrics.IncBrokerError("kafka", messageTypeBroadcast)
+		if k.client.fallbackToSpool(ctx, spoolTransportKafka, messageTypeBroadcast, body, err) {
+			span.RecordError(err)
+			return nil
+		}
+		return err
+	}
+
+	k.client.idempotency.remember(key, nil)
+	return nil
 }
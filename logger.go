@@ -1,7 +1,11 @@
 package convoy_go
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"log/slog"
+	"strings"
 
 	log "github.com/frain-dev/convoy/pkg/log"
 )
@@ -30,18 +34,132 @@ const (
 	DebugLevel
 )
 
-// iLogger is a stripped down logging interface that
-// clients can implement.
-type iLogger interface {
+// Logger is the logging interface the client uses. NewLogger's logger and
+// SlogLogger implement it.
+type Logger interface {
 	Debugf(format string, v ...interface{})
 	Errorf(format string, v ...interface{})
 	Infof(format string, v ...interface{})
 	Warnf(format string, v ...interface{})
 }
 
+// FieldLogger is a Logger that also takes structured fields as key-value
+// pairs, like SlogLogger. The client logs each API call with fields such as
+// method, path, status and duration; with a plain Logger they are appended
+// to the message as key=value text.
+type FieldLogger interface {
+	Logger
+	Log(level Level, msg string, keyvals ...interface{})
+	Enabled(level Level) bool
+}
+
 func NewLogger(out io.Writer, lvl Level) *log.Logger {
 	logger := log.NewLogger(out)
 	logger.SetLevel(log.Level(lvl))
 
 	return logger
 }
+
+// SlogLogger adapts a *slog.Logger to Logger and FieldLogger.
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger that writes to l, or to slog.Default when l
+// is nil.
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	if l == nil {
+		l = slog.Default()
+	}
+
+	return &SlogLogger{logger: l}
+}
+
+func (s *SlogLogger) Debugf(format string, v ...interface{}) {
+	s.logger.Debug(fmt.Sprintf(format, v...))
+}
+
+func (s *SlogLogger) Errorf(format string, v ...interface{}) {
+	s.logger.Error(fmt.Sprintf(format, v...))
+}
+
+func (s *SlogLogger) Infof(format string, v ...interface{}) {
+	s.logger.Info(fmt.Sprintf(format, v...))
+}
+
+func (s *SlogLogger) Warnf(format string, v ...interface{}) {
+	s.logger.Warn(fmt.Sprintf(format, v...))
+}
+
+func (s *SlogLogger) Log(level Level, msg string, keyvals ...interface{}) {
+	s.logger.Log(context.Background(), slogLevel(level), msg, keyvals...)
+}
+
+func (s *SlogLogger) Enabled(level Level) bool {
+	return s.logger.Enabled(context.Background(), slogLevel(level))
+}
+
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case FatalLevel:
+		return slog.LevelError + 4
+	case ErrorLevel:
+		return slog.LevelError
+	case WarnLevel:
+		return slog.LevelWarn
+	case InfoLevel:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// logEnabled reports whether l logs at level. Loggers that can't tell are
+// assumed to.
+func logEnabled(l Logger, level Level) bool {
+	switch l := l.(type) {
+	case FieldLogger:
+		return l.Enabled(level)
+	case *log.Logger:
+		lvl, err := log.Level(level).ToLogrusLevel()
+		return err != nil || l.WithLogger().IsLevelEnabled(lvl)
+	default:
+		return true
+	}
+}
+
+// logFields logs msg with key-value pairs, as fields when l supports them.
+func logFields(l Logger, level Level, msg string, keyvals ...interface{}) {
+	switch l := l.(type) {
+	case FieldLogger:
+		l.Log(level, msg, keyvals...)
+		return
+	case *log.Logger:
+		lvl, err := log.Level(level).ToLogrusLevel()
+		if err == nil {
+			fields := log.Fields{}
+			for i := 0; i+1 < len(keyvals); i += 2 {
+				fields[fmt.Sprint(keyvals[i])] = keyvals[i+1]
+			}
+			l.WithFields(fields).Log(lvl, msg)
+			return
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", keyvals[i], keyvals[i+1])
+	}
+
+	switch level {
+	case FatalLevel, ErrorLevel:
+		l.Errorf("%s", b.String())
+	case WarnLevel:
+		l.Warnf("%s", b.String())
+	case InfoLevel:
+		l.Infof("%s", b.String())
+	default:
+		l.Debugf("%s", b.String())
+	}
+}
@@ -0,0 +1,109 @@
+package convoy_go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogLoggerLogsRedactedAPICalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"ep-1","secrets":[{"uid":"s1","value":"response-secret"}]}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var out bytes.Buffer
+	logger := NewSlogLogger(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	c := New(srv.URL, "test-api-key", "test-project-id", OptionLogger(logger))
+
+	_, err := c.Endpoints.Create(context.Background(), &CreateEndpointRequest{
+		Name:   "billing",
+		URL:    "https://example.com/hook",
+		Secret: "request-secret",
+		Authentication: &EndpointAuth{
+			Type:   EndpointAuthAPIKey,
+			ApiKey: &ApiKeyAuth{HeaderName: "X-Key", HeaderValue: "header-secret"},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	require.NotContains(t, out.String(), "test-api-key")
+	require.NotContains(t, out.String(), "request-secret")
+	require.NotContains(t, out.String(), "header-secret")
+	require.NotContains(t, out.String(), "response-secret")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	require.Equal(t, "DEBUG", entry["level"])
+	require.Equal(t, http.MethodPost, entry["method"])
+	require.Equal(t, "/projects/test-project-id/endpoints", entry["path"])
+	require.Equal(t, float64(http.StatusOK), entry["status"])
+	require.Contains(t, entry, "duration")
+	require.Contains(t, entry["request_body"], `"name":"billing"`)
+}
+
+func TestLoggerSkipsAPICallsAboveDebugLevel(t *testing.T) {
+	c, _ := newTestClient(t, `{"status":true,"message":"ok","data":null}`)
+
+	var out bytes.Buffer
+	c.log = NewSlogLogger(slog.New(slog.NewTextHandler(&out, nil)))
+
+	_, err := c.Endpoints.Pause(context.Background(), "ep-1")
+	require.NoError(t, err)
+	require.Empty(t, out.String())
+
+	c.log = NewLogger(&out, ErrorLevel)
+	_, err = c.Endpoints.Pause(context.Background(), "ep-1")
+	require.NoError(t, err)
+	require.Empty(t, out.String())
+
+	c.log = NewLogger(&out, DebugLevel)
+	_, err = c.Endpoints.Pause(context.Background(), "ep-1")
+	require.NoError(t, err)
+	require.Contains(t, out.String(), `"path":"/projects/test-project-id/endpoints/ep-1/pause"`)
+}
+
+func TestLoggerRedactsPortalRefreshTokens(t *testing.T) {
+	c, _ := newTestClient(t, `{"status":true,"message":"ok","data":"portal-token"}`)
+
+	var out bytes.Buffer
+	c.log = NewSlogLogger(slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	token, err := c.PortalLinks.RefreshToken(context.Background(), "pl-1")
+	require.NoError(t, err)
+	require.Equal(t, "portal-token", token)
+
+	require.NotContains(t, out.String(), "portal-token")
+	require.Contains(t, out.String(), redacted)
+}
+
+func TestRedactHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Bearer api-key")
+	h.Set("Cookie", "session=abc")
+	h.Set("Set-Cookie", "session=abc; HttpOnly")
+	h.Set(DefaultSigHeader, "t=1,v1=deadbeef")
+	h.Set("X-Tenant", "acme")
+
+	out := redactHeader(h)
+	require.Equal(t, "Bearer "+redacted, out.Get("Authorization"))
+	require.Equal(t, redacted, out.Get("Cookie"))
+	require.Equal(t, redacted, out.Get("Set-Cookie"))
+	require.Equal(t, redacted, out.Get(DefaultSigHeader))
+	require.Equal(t, "acme", out.Get("X-Tenant"))
+	require.Equal(t, "Bearer api-key", h.Get("Authorization"))
+}
+
+func TestRedactBodyCloudCredentials(t *testing.T) {
+	body := redactBody([]byte(`{"google":{"service_account":"{\"private_key\":\"pk\"}"},"sqs":{"access_key_id":"AKIA1","secret_access_key":"sk1"}}`), false)
+
+	require.NotContains(t, body, "pk")
+	require.NotContains(t, body, "AKIA1")
+	require.NotContains(t, body, "sk1")
+}
@@ -0,0 +1,87 @@
+package convoy_go
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// Metrics receives the client's measurements so they can be exported to
+// Prometheus, OpenTelemetry or anything else. Its methods are called
+// concurrently and on hot paths, so they must be safe for concurrent use
+// and must not block. Embed NopMetrics to implement only some of them.
+type Metrics interface {
+	// ObserveRequest records an API call by the resource and operation of
+	// its route, e.g. "endpoints" and "pause", with the response status
+	// code, or 0 when no response arrived.
+	ObserveRequest(resource, operation string, statusCode int, duration time.Duration)
+
+	// IncPublisherRetry counts the AsyncPublisher named publisher retrying
+	// an event of msgType: "single", "fanout" or "broadcast".
+	IncPublisherRetry(publisher, msgType string)
+
+	// SetPublisherQueueDepth reports the number of events waiting in the
+	// queue of the AsyncPublisher named publisher.
+	SetPublisherQueueDepth(publisher string, depth int64)
+
+	// IncBrokerError counts a failed write to a broker, "kafka" or "sqs".
+	IncBrokerError(broker, msgType string)
+
+	// IncWebhookVerification counts a webhook verification by its result,
+	// one of the Verification* constants.
+	IncWebhookVerification(result string)
+}
+
+// NopMetrics discards every measurement.
+type NopMetrics struct{}
+
+func (NopMetrics) ObserveRequest(string, string, int, time.Duration) {}
+func (NopMetrics) IncPublisherRetry(string, string)                  {}
+func (NopMetrics) SetPublisherQueueDepth(string, int64)              {}
+func (NopMetrics) IncBrokerError(string, string)                     {}
+func (NopMetrics) IncWebhookVerification(string)                     {}
+
+// Webhook verification results reported to Metrics.
+const (
+	VerificationOK               = "ok"
+	VerificationInvalidHeader    = "invalid_header"
+	VerificationInvalidEncoding  = "invalid_encoding"
+	VerificationInvalidSignature = "invalid_signature"
+	VerificationInvalidHash      = "invalid_hash_algorithm"
+	VerificationExpired          = "timestamp_expired"
+	VerificationError            = "error"
+)
+
+// OptionMetrics reports the client's API calls, publisher retries and queue
+// depth, and broker write errors to m. A nil m discards them.
+func OptionMetrics(m Metrics) func(c *Client) {
+	return func(c *Client) {
+		if m == nil {
+			m = NopMetrics{}
+		}
+
+		c.metrics = m
+	}
+}
+
+// verificationResult maps a verification error to its Metrics result.
+func verificationResult(err error) string {
+	switch {
+	case err == nil:
+		return VerificationOK
+	case errors.Is(err, ErrInvalidHeader), errors.Is(err, ErrInvalidSignatureHeader):
+		return VerificationInvalidHeader
+	case errors.Is(err, ErrInvalidEncoding), errors.Is(err, hex.ErrLength),
+		errors.As(err, new(hex.InvalidByteError)), errors.As(err, new(base64.CorruptInputError)):
+		return VerificationInvalidEncoding
+	case errors.Is(err, ErrInvalidSignature):
+		return VerificationInvalidSignature
+	case errors.Is(err, ErrInvalidHashAlgorithm):
+		return VerificationInvalidHash
+	case errors.Is(err, ErrTimestampExpired):
+		return VerificationExpired
+	default:
+		return VerificationError
+	}
+}
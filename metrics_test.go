@@ -0,0 +1,155 @@
+package convoy_go
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMetrics struct {
+	NopMetrics
+
+	mu            sync.Mutex
+	requests      []string
+	retries       map[string]int
+	depths        map[string]int64
+	verifications map[string]int
+}
+
+func (m *recordingMetrics) ObserveRequest(resource, operation string, statusCode int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = append(m.requests, fmt.Sprintf("%s %s %d", resource, operation, statusCode))
+}
+
+func (m *recordingMetrics) IncPublisherRetry(publisher, msgType string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.retries == nil {
+		m.retries = map[string]int{}
+	}
+	m.retries[publisher+" "+msgType]++
+}
+
+func (m *recordingMetrics) SetPublisherQueueDepth(publisher string, depth int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.depths == nil {
+		m.depths = map[string]int64{}
+	}
+	m.depths[publisher] = depth
+}
+
+func (m *recordingMetrics) IncWebhookVerification(result string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.verifications == nil {
+		m.verifications = map[string]int{}
+	}
+	m.verifications[result]++
+}
+
+func TestMetricsObserveRequestsAndRetries(t *testing.T) {
+	metrics := &recordingMetrics{}
+
+	calls := 0
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method == http.MethodPost && calls == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":false,"message":"unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}, OptionMetrics(metrics))
+
+	ctx := context.Background()
+	_, err := c.Endpoints.Pause(ctx, "ep-1")
+	require.NoError(t, err)
+
+	p, err := NewAsyncPublisher(c, &AsyncPublisherOptions{Name: "orders", Workers: 1, RetryBackoff: time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, p.Publish(ctx, &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event"}))
+	require.NoError(t, p.Close(ctx))
+
+	require.Equal(t, []string{"endpoints pause 200", "events create 503", "events create 200"}, metrics.requests)
+	require.Equal(t, map[string]int{"orders single": 1}, metrics.retries)
+}
+
+func TestMetricsLabelQueueDepthByPublisher(t *testing.T) {
+	metrics := &recordingMetrics{}
+
+	release := make(chan struct{})
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}, OptionMetrics(metrics))
+
+	ctx := context.Background()
+	orders, err := NewAsyncPublisher(c, &AsyncPublisherOptions{Name: "orders", Workers: 1})
+	require.NoError(t, err)
+	billing, err := NewAsyncPublisher(c, &AsyncPublisherOptions{Workers: 1})
+	require.NoError(t, err)
+
+	// The workers hold the first event of each publisher; the rest queue.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, orders.Publish(ctx, &CreateEventRequest{EndpointID: "ep-1", EventType: "order.created"}))
+	}
+	require.NoError(t, billing.Publish(ctx, &CreateEventRequest{EndpointID: "ep-1", EventType: "invoice.paid"}))
+
+	require.Eventually(t, func() bool {
+		metrics.mu.Lock()
+		defer metrics.mu.Unlock()
+		return metrics.depths["orders"] == 2 && metrics.depths[DefaultPublisherName] == 0
+	}, time.Second, 5*time.Millisecond)
+
+	close(release)
+	require.NoError(t, orders.Close(ctx))
+	require.NoError(t, billing.Close(ctx))
+	require.Equal(t, map[string]int64{"orders": 0, DefaultPublisherName: 0}, metrics.depths)
+}
+
+func TestMetricsNilDiscardsMeasurements(t *testing.T) {
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}, OptionMetrics(nil))
+
+	p := newTestPublisher(t, c, &AsyncPublisherOptions{Workers: 1})
+	require.NoError(t, p.Publish(context.Background(), &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event"}))
+	require.NoError(t, p.Flush(context.Background()))
+	require.Equal(t, int64(1), p.Stats().Published)
+}
+
+func TestMetricsCountWebhookVerifications(t *testing.T) {
+	metrics := &recordingMetrics{}
+	webhook := NewWebhook(&WebhookOpts{Secret: "secret", Metrics: metrics})
+
+	payload := []byte(`{"m":1}`)
+	expired := fmt.Sprintf("t=%d,v1=abcd", time.Now().Add(-time.Hour).Unix())
+
+	require.NoError(t, webhook.VerifyPayload(payload, hexSignature(webhook, payload)))
+	require.ErrorIs(t, webhook.VerifyPayload(payload, "deadbeef"), ErrInvalidSignature)
+	require.ErrorIs(t, webhook.VerifyPayload(payload, expired), ErrTimestampExpired)
+	require.Error(t, webhook.VerifyPayload(payload, "not-hex"))
+
+	require.Equal(t, map[string]int{
+		VerificationOK:               1,
+		VerificationInvalidSignature: 1,
+		VerificationExpired:          1,
+		VerificationInvalidEncoding:  1,
+	}, metrics.verifications)
+}
+
+func hexSignature(w *Webhook, payload []byte) string {
+	sig, _ := w.generateSignature(&signedHeader{}, payload)
+	return fmt.Sprintf("%x", sig)
+}
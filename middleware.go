@@ -0,0 +1,61 @@
+package convoy_go
+
+import (
+	"context"
+	"net/http"
+)
+
+// RoundTripFunc sends an API request and returns Convoy's response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps every API call. It can change the request before calling
+// next, e.g. to add headers, and observe or replace the response after.
+// RouteFromContext tells it which call it is wrapping.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+type routeContextKey struct{}
+
+// OptionMiddleware adds mw around every API call. Middlewares run in the
+// order they're added, the first one outermost. They run after the client
+// has set its headers, so they can override them.
+func OptionMiddleware(mw Middleware) func(c *Client) {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw)
+	}
+}
+
+// RequestEditor turns a function shaped like the generated client's
+// RequestEditorFn into a Middleware.
+func RequestEditor(fn func(ctx context.Context, req *http.Request) error) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			err := fn(req.Context(), req)
+			if err != nil {
+				return nil, err
+			}
+
+			return next(req)
+		}
+	}
+}
+
+// RouteFromContext returns the route of the API call a request context
+// belongs to. It's set for middlewares.
+func RouteFromContext(ctx context.Context) (Route, bool) {
+	r, ok := ctx.Value(routeContextKey{}).(Route)
+	return r, ok
+}
+
+// chain builds the client's round trip: the middlewares around its HTTP
+// client.
+func (c *Client) chain() RoundTripFunc {
+	rt := RoundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return c.client.Do(req)
+	})
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		rt = c.middleware[i](rt)
+	}
+
+	return rt
+}
@@ -0,0 +1,77 @@
+package convoy_go
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareRunsInOrderAroundEachCall(t *testing.T) {
+	var tenant string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant = r.Header.Get("X-Tenant")
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"ep-1"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				r, ok := RouteFromContext(req.Context())
+				require.True(t, ok)
+				calls = append(calls, name+" "+r.Resource+" "+r.Operation)
+
+				resp, err := next(req)
+				calls = append(calls, name+" done "+resp.Status)
+				return resp, err
+			}
+		}
+	}
+
+	c := New(srv.URL, "test-api-key", "test-project-id",
+		OptionMiddleware(record("outer")),
+		OptionMiddleware(record("inner")),
+		OptionMiddleware(RequestEditor(func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("X-Tenant", "tenant-1")
+			return nil
+		})),
+	)
+
+	_, err := c.Endpoints.Pause(context.Background(), "ep-1")
+	require.NoError(t, err)
+
+	require.Equal(t, "tenant-1", tenant)
+	require.Equal(t, []string{
+		"outer endpoints pause",
+		"inner endpoints pause",
+		"inner done 200 OK",
+		"outer done 200 OK",
+	}, calls)
+}
+
+func TestMiddlewareCanShortCircuitCalls(t *testing.T) {
+	c := New("https://convoy.invalid/api/v1", "test-api-key", "test-project-id",
+		OptionMiddleware(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"status":false,"message":"chaos"}`)),
+				}, nil
+			}
+		}),
+	)
+
+	_, err := c.Events.Find(context.Background(), "evt-1")
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	require.Equal(t, "chaos", apiErr.Message)
+}
@@ -2,26 +2,58 @@ package convoy_go
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"time"
 )
 
+var (
+	ErrInvalidEmbedURL   = errors.New("invalid portal embed url")
+	ErrStaticPortalToken = errors.New("static_token portal links can't be embedded with an expiring token")
+)
+
+// PortalAuthType is how a portal link's token is issued.
+type PortalAuthType string
+
+const (
+	// PortalAuthTypeStaticToken links carry a long-lived token in their URL.
+	PortalAuthTypeStaticToken PortalAuthType = "static_token"
+	// PortalAuthTypeRefreshToken links need a fresh token from
+	// PortalLink.RefreshToken for each session.
+	PortalAuthTypeRefreshToken PortalAuthType = "refresh_token"
+)
+
+// PortalLinkService is the portal links API. *PortalLink implements it;
+// convoymock has a mock.
+type PortalLinkService interface {
+	All(ctx context.Context) (*ListPortalLinkResponse, error)
+	Create(ctx context.Context, body *CreatePortalLinkRequest) (*PortalLinkResponse, error)
+	Find(ctx context.Context, portalLinkID string) (*PortalLinkResponse, error)
+	Update(ctx context.Context, portalLinkID string, body *UpdatePortalLinkRequest) (*PortalLinkResponse, error)
+	Revoke(ctx context.Context, portalLinkID string) error
+	RefreshToken(ctx context.Context, portalLinkID string) (string, error)
+	EmbedURL(ctx context.Context, portalLinkID string) (string, error)
+}
+
 type PortalLink struct {
 	client *Client
 }
 
 type CreatePortalLinkRequest struct {
-	Name              string   `json:"name"`
-	Endpoints         []string `json:"endpoints"`
-	OwnerID           string   `json:"owner_id"`
-	CanManageEndpoint bool     `json:"can_manage_endpoint"`
+	Name              string         `json:"name"`
+	Endpoints         []string       `json:"endpoints"`
+	OwnerID           string         `json:"owner_id"`
+	CanManageEndpoint bool           `json:"can_manage_endpoint"`
+	AuthType          PortalAuthType `json:"auth_type,omitempty"`
 }
 
 type UpdatePortalLinkRequest struct {
-	Name              string   `json:"name"`
-	Endpoints         []string `json:"endpoints"`
-	OwnerID           string   `json:"owner_id"`
-	CanManageEndpoint bool     `json:"can_manage_endpoint"`
+	Name              string         `json:"name"`
+	Endpoints         []string       `json:"endpoints"`
+	OwnerID           string         `json:"owner_id"`
+	CanManageEndpoint bool           `json:"can_manage_endpoint"`
+	AuthType          PortalAuthType `json:"auth_type,omitempty"`
 }
 
 type PortalLinkResponse struct {
@@ -33,12 +65,14 @@ type PortalLinkResponse struct {
 	EndpointCount     int                `json:"endpoint_count"`
 	CanManageEndpoint bool               `json:"can_manage_endpoint"`
 	Token             string             `json:"token"`
+	AuthType          PortalAuthType     `json:"auth_type"`
+	AuthKey           string             `json:"auth_key"`
 	EndpointsMetadata []EndpointResponse `json:"endpoints_metadata"`
 	URL               string             `json:"url"`
 
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt time.Time
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at"`
 }
 
 type ListPortalLinkResponse struct {
@@ -113,7 +147,7 @@ func (p *PortalLink) Update(ctx context.Context, portalLinkID string, body *Upda
 }
 
 func (p *PortalLink) Revoke(ctx context.Context, portalLinkID string) error {
-	url, err := addOptions(p.generateUrl()+"/"+portalLinkID, nil)
+	url, err := addOptions(p.generateUrl()+"/"+portalLinkID+"/revoke", nil)
 	if err != nil {
 		return err
 	}
@@ -126,6 +160,53 @@ func (p *PortalLink) Revoke(ctx context.Context, portalLinkID string) error {
 	return nil
 }
 
+// RefreshToken issues a new auth token for a refresh_token portal link.
+func (p *PortalLink) RefreshToken(ctx context.Context, portalLinkID string) (string, error) {
+	url, err := addOptions(p.generateUrl()+"/"+portalLinkID+"/refresh_token", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var token string
+	err = getResource(ctx, p.client, url, &token)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// EmbedURL returns the link's URL with a freshly issued token, for serving
+// the portal in an iframe. The token expires when Convoy's refresh_token
+// policy says so; that's the only expiry enforced, so static_token links,
+// whose tokens never expire, are refused with ErrStaticPortalToken.
+func (p *PortalLink) EmbedURL(ctx context.Context, portalLinkID string) (string, error) {
+	link, err := p.Find(ctx, portalLinkID)
+	if err != nil {
+		return "", err
+	}
+
+	if link.AuthType != PortalAuthTypeRefreshToken {
+		return "", ErrStaticPortalToken
+	}
+
+	u, err := url.Parse(link.URL)
+	if err != nil || !u.IsAbs() {
+		return "", ErrInvalidEmbedURL
+	}
+
+	token, err := p.RefreshToken(ctx, portalLinkID)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
 func (p *PortalLink) generateUrl() string {
 	return fmt.Sprintf("%s/projects/%s/portal-links", p.client.baseURL, p.client.projectID)
 }
@@ -0,0 +1,37 @@
+package convoy_go
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPortalLinkTestClient(t *testing.T, authType PortalAuthType) *Client {
+	return newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/test-project-id/portal-links/pl-1":
+			_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"pl-1","auth_type":"` + string(authType) + `","url":"https://convoy.example.com/portal?token=old&owner_id=o1"}}`))
+		case "/projects/test-project-id/portal-links/pl-1/refresh_token":
+			_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":"fresh-token"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestEmbedURLIssuesAFreshToken(t *testing.T) {
+	c := newPortalLinkTestClient(t, PortalAuthTypeRefreshToken)
+
+	embed, err := c.PortalLinks.EmbedURL(context.Background(), "pl-1")
+	require.NoError(t, err)
+	require.Equal(t, "https://convoy.example.com/portal?owner_id=o1&token=fresh-token", embed)
+}
+
+func TestEmbedURLRefusesStaticTokens(t *testing.T) {
+	c := newPortalLinkTestClient(t, PortalAuthTypeStaticToken)
+
+	_, err := c.PortalLinks.EmbedURL(context.Background(), "pl-1")
+	require.ErrorIs(t, err, ErrStaticPortalToken)
+}
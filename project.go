@@ -12,6 +12,14 @@ var (
 	ErrNotProjectResponse     = errors.New("invalid project response")
 )
 
+// ProjectService is the projects API. *Project implements it; convoymock
+// has a mock.
+type ProjectService interface {
+	Find(ctx context.Context, projectID string) (*ProjectResponse, error)
+	Update(ctx context.Context, projectID string, body *CreateProjectRequest) (*ProjectResponse, error)
+	Delete(ctx context.Context, projectID string) error
+}
+
 type Project struct {
 	client *Client
 }
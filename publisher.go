@@ -0,0 +1,410 @@
+package convoy_go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrPublisherClosed = errors.New("publisher is closed")
+	ErrEventDropped    = errors.New("event dropped from full publisher queue")
+)
+
+const (
+	messageTypeSingle    = "single"
+	messageTypeFanout    = "fanout"
+	messageTypeBroadcast = "broadcast"
+	messageTypeDynamic   = "dynamic"
+)
+
+// OverflowPolicy decides what an AsyncPublisher does with an event when its
+// queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes Publish wait for room in the queue, or for its
+	// context to be done.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest evicts the oldest queued event to make room.
+	OverflowDropOldest
+
+	// OverflowSpill appends the event to the client's spool (see
+	// OptionSpool) instead of queueing it.
+	OverflowSpill
+)
+
+var (
+	DefaultPublisherQueueSize    = 1000
+	DefaultPublisherWorkers      = 4
+	DefaultPublisherMaxRetries   = 3
+	DefaultPublisherRetryBackoff = 500 * time.Millisecond
+	DefaultPublisherName         = "default"
+)
+
+type AsyncPublisherOptions struct {
+	// Name labels the publisher's metrics, so several publishers can be told
+	// apart. Defaults to DefaultPublisherName.
+	Name string
+
+	// QueueSize is the number of events buffered in memory.
+	QueueSize int
+
+	// Workers is the number of goroutines sending events to Convoy.
+	Workers int
+
+	// MaxRetries is the number of times a send that failed because Convoy
+	// was unreachable, erroring (5xx) or rate limiting (429) is retried
+	// before the event is counted as failed. Other rejections, e.g. 400 or
+	// 401, fail at once. Zero uses DefaultPublisherMaxRetries; a
+	// negative value disables retries.
+	MaxRetries int
+
+	// RetryBackoff is the base wait between retries; it grows linearly with
+	// each attempt.
+	RetryBackoff time.Duration
+
+	Overflow OverflowPolicy
+
+	// OnError, if set, is called for every event that is dropped or that
+	// still fails after MaxRetries. Events that end up in the client's spool
+	// are not reported.
+	OnError func(msgType string, body interface{}, err error)
+}
+
+// PublisherStats is a point-in-time snapshot of an AsyncPublisher's counters.
+type PublisherStats struct {
+	Queued    int64
+	Inflight  int64
+	Published int64
+	Failed    int64
+	Dropped   int64
+	Spilled   int64
+}
+
+// AsyncPublisher sends events to Convoy from a bounded in-memory queue so
+// callers don't wait on the API.
+type AsyncPublisher struct {
+	client *Client
+	opts   *AsyncPublisherOptions
+	queue  chan *publishJob
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	// mu guards closed and every send on queue, so Close never closes the
+	// channel under a concurrent Publish.
+	mu     sync.RWMutex
+	closed bool
+
+	queued    atomic.Int64
+	inflight  atomic.Int64
+	published atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	spilled   atomic.Int64
+}
+
+type publishJob struct {
+	msgType string
+	body    interface{}
+}
+
+// newJob queues a copy of body, with its idempotency key assigned once so
+// every retry of the job sends the same key.
+func (p *AsyncPublisher) newJob(msgType string, body interface{}) *publishJob {
+	switch b := body.(type) {
+	case *CreateEventRequest:
+		ev := *b
+		body = &ev
+	case *CreateFanoutEventRequest:
+		ev := *b
+		body = &ev
+	case *CreateBroadcastEventRequest:
+		ev := *b
+		body = &ev
+	}
+
+	p.client.idempotency.assignKey(msgType, body)
+	return &publishJob{msgType: msgType, body: body}
+}
+
+func NewAsyncPublisher(c *Client, opts *AsyncPublisherOptions) (*AsyncPublisher, error) {
+	if opts == nil {
+		opts = &AsyncPublisherOptions{}
+	}
+
+	// Defaults go on a copy, so the caller's options can be reused.
+	o := *opts
+	opts = &o
+
+	if isStringEmpty(opts.Name) {
+		opts.Name = DefaultPublisherName
+	}
+
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultPublisherQueueSize
+	}
+
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultPublisherWorkers
+	}
+
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultPublisherMaxRetries
+	}
+
+	if opts.RetryBackoff == 0 {
+		opts.RetryBackoff = DefaultPublisherRetryBackoff
+	}
+
+	if opts.Overflow == OverflowSpill && c.spool == nil {
+		return nil, errors.New("the spill overflow policy requires a client spool")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &AsyncPublisher{
+		client: c,
+		opts:   opts,
+		queue:  make(chan *publishJob, opts.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for i := 0; i < opts.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+
+	return p, nil
+}
+
+// Publish queues an event for a single endpoint.
+func (p *AsyncPublisher) Publish(ctx context.Context, body *CreateEventRequest) error {
+	return p.enqueue(ctx, p.newJob(messageTypeSingle, body))
+}
+
+// PublishFanout queues an event for every endpoint of an owner.
+func (p *AsyncPublisher) PublishFanout(ctx context.Context, body *CreateFanoutEventRequest) error {
+	return p.enqueue(ctx, p.newJob(messageTypeFanout, body))
+}
+
+// PublishBroadcast queues an event for every subscribed endpoint.
+func (p *AsyncPublisher) PublishBroadcast(ctx context.Context, body *CreateBroadcastEventRequest) error {
+	return p.enqueue(ctx, p.newJob(messageTypeBroadcast, body))
+}
+
+// Stats returns the publisher's current counters.
+func (p *AsyncPublisher) Stats() PublisherStats {
+	return PublisherStats{
+		Queued:    p.queued.Load(),
+		Inflight:  p.inflight.Load(),
+		Published: p.published.Load(),
+		Failed:    p.failed.Load(),
+		Dropped:   p.dropped.Load(),
+		Spilled:   p.spilled.Load(),
+	}
+}
+
+// Flush waits until every queued event has been sent or has failed.
+func (p *AsyncPublisher) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if p.queued.Load() == 0 && p.inflight.Load() == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops accepting events and waits for the queue to drain. If ctx is
+// done first, in-flight sends are cancelled and ctx's error is returned.
+func (p *AsyncPublisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (p *AsyncPublisher) enqueue(ctx context.Context, job *publishJob) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPublisherClosed
+	}
+
+	// Count the job before it becomes visible to workers so Flush never
+	// observes an empty publisher while the job sits in the channel.
+	p.addQueued(1)
+
+	select {
+	case p.queue <- job:
+		return nil
+	default:
+	}
+
+	switch p.opts.Overflow {
+	case OverflowDropOldest:
+		for {
+			select {
+			case p.queue <- job:
+				return nil
+			default:
+			}
+
+			select {
+			case old := <-p.queue:
+				p.addQueued(-1)
+				p.dropped.Add(1)
+				p.reportError(old, ErrEventDropped)
+			default:
+			}
+		}
+	case OverflowSpill:
+		p.addQueued(-1)
+		return p.spill(job)
+	default:
+		select {
+		case p.queue <- job:
+			return nil
+		case <-ctx.Done():
+			p.addQueued(-1)
+			return ctx.Err()
+		}
+	}
+}
+
+// addQueued adjusts the number of queued events and reports it to the
+// client's Metrics.
+func (p *AsyncPublisher) addQueued(n int64) {
+	p.client.metrics.SetPublisherQueueDepth(p.opts.Name, p.queued.Add(n))
+}
+
+func (p *AsyncPublisher) work() {
+	defer p.wg.Done()
+
+	for job := range p.queue {
+		p.inflight.Add(1)
+		p.addQueued(-1)
+
+		p.finish(job, p.sendWithRetry(job))
+		p.inflight.Add(-1)
+	}
+}
+
+func (p *AsyncPublisher) finish(job *publishJob, err error) {
+	if err == nil {
+		p.published.Add(1)
+		return
+	}
+
+	// Convoy being down is what the spool is for; anything else is a
+	// rejection the caller needs to hear about.
+	if isUnreachable(err) && p.client.spool != nil && p.spill(job) == nil {
+		return
+	}
+
+	p.failed.Add(1)
+	p.reportError(job, err)
+}
+
+func (p *AsyncPublisher) sendWithRetry(job *publishJob) error {
+	var err error
+	for attempt := 0; attempt <= p.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-p.ctx.Done():
+				return err
+			case <-time.After(time.Duration(attempt) * p.opts.RetryBackoff):
+			}
+			p.client.metrics.IncPublisherRetry(p.opts.Name, job.msgType)
+		}
+
+		// Retries are ours to make; only spool once they are exhausted.
+		err = p.send(withoutSpool(p.ctx), job)
+		if err == nil {
+			return nil
+		}
+
+		p.client.log.Warnf("publisher: attempt %d to send %s event failed - %+v", attempt+1, job.msgType, err)
+
+		// Resending an event Convoy rejected only gets it rejected again.
+		if !isUnreachable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (p *AsyncPublisher) send(ctx context.Context, job *publishJob) error {
+	switch body := job.body.(type) {
+	case *CreateEventRequest:
+		_, err := p.client.Events.Create(ctx, body)
+		return err
+	case *CreateFanoutEventRequest:
+		_, err := p.client.Events.FanoutEvent(ctx, body)
+		return err
+	case *CreateBroadcastEventRequest:
+		_, err := p.client.Events.BroadcastEvent(ctx, body)
+		return err
+	default:
+		return fmt.Errorf("publisher: unsupported event body %T", job.body)
+	}
+}
+
+func (p *AsyncPublisher) spill(job *publishJob) error {
+	rec, err := newSpoolRecord(job.msgType, job.body)
+	if err != nil {
+		return err
+	}
+
+	err = p.client.spool.Append(rec)
+	if err != nil {
+		return err
+	}
+
+	p.spilled.Add(1)
+	return nil
+}
+
+func (p *AsyncPublisher) reportError(job *publishJob, err error) {
+	if p.opts.OnError != nil {
+		p.opts.OnError(job.msgType, job.body, err)
+	}
+}
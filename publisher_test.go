@@ -0,0 +1,208 @@
+package convoy_go
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newPublisherTestClient(t *testing.T, handler http.HandlerFunc, options ...Option) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return New(srv.URL, "test-api-key", "test-project-id", options...)
+}
+
+// newTestPublisher returns a publisher that is closed when the test ends,
+// cancelling sends still in flight after a short grace period.
+func newTestPublisher(t *testing.T, c *Client, opts *AsyncPublisherOptions) *AsyncPublisher {
+	t.Helper()
+
+	p, err := NewAsyncPublisher(c, opts)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_ = p.Close(ctx)
+	})
+
+	return p
+}
+
+func TestAsyncPublisherFlushSendsEveryEvent(t *testing.T) {
+	var received atomic.Int64
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	})
+
+	p := newTestPublisher(t, c, &AsyncPublisherOptions{QueueSize: 10, Workers: 2})
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, p.Publish(ctx, &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event"}))
+	}
+	require.NoError(t, p.PublishFanout(ctx, &CreateFanoutEventRequest{OwnerID: "owner-1", EventType: "test.event"}))
+	require.NoError(t, p.PublishBroadcast(ctx, &CreateBroadcastEventRequest{EventType: "test.event"}))
+
+	require.NoError(t, p.Flush(ctx))
+	require.Equal(t, int64(7), received.Load())
+
+	stats := p.Stats()
+	require.Equal(t, int64(7), stats.Published)
+	require.Zero(t, stats.Queued)
+	require.Zero(t, stats.Inflight)
+	require.Zero(t, stats.Failed)
+
+	require.NoError(t, p.Close(ctx))
+	require.ErrorIs(t, p.Publish(ctx, &CreateEventRequest{}), ErrPublisherClosed)
+}
+
+func TestAsyncPublisherRetriesThenFails(t *testing.T) {
+	var attempts atomic.Int64
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"status":false,"message":"upstream unavailable"}`))
+	})
+
+	var reported atomic.Int64
+	p := newTestPublisher(t, c, &AsyncPublisherOptions{
+		Workers:      1,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+		OnError: func(msgType string, body interface{}, err error) {
+			require.Equal(t, messageTypeSingle, msgType)
+			reported.Add(1)
+		},
+	})
+
+	ctx := context.Background()
+	require.NoError(t, p.Publish(ctx, &CreateEventRequest{EndpointID: "ep-1"}))
+	require.NoError(t, p.Close(ctx))
+
+	require.Equal(t, int64(3), attempts.Load())
+	require.Equal(t, int64(1), reported.Load())
+	require.Equal(t, int64(1), p.Stats().Failed)
+}
+
+func TestAsyncPublisherDoesNotRetryRejections(t *testing.T) {
+	var attempts atomic.Int64
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":false,"message":"invalid event type"}`))
+	})
+
+	var reported error
+	p := newTestPublisher(t, c, &AsyncPublisherOptions{
+		Workers:      1,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+		OnError: func(msgType string, body interface{}, err error) {
+			reported = err
+		},
+	})
+
+	ctx := context.Background()
+	require.NoError(t, p.Publish(ctx, &CreateEventRequest{EndpointID: "ep-1"}))
+	require.NoError(t, p.Close(ctx))
+
+	require.Equal(t, int64(1), attempts.Load())
+	var apiErr *APIError
+	require.ErrorAs(t, reported, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
+
+// newBlockedPublisherTestClient returns a client whose server holds every
+// request until the test ends, so the publisher's workers stay busy.
+func newBlockedPublisherTestClient(t *testing.T, options ...Option) *Client {
+	t.Helper()
+
+	release := make(chan struct{})
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}, options...)
+
+	// Registered after the server's cleanup, so it runs first and lets the
+	// server shut down.
+	t.Cleanup(func() { close(release) })
+
+	return c
+}
+
+func TestAsyncPublisherOverflowPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("drop_oldest", func(t *testing.T) {
+		c := newBlockedPublisherTestClient(t)
+		p := newTestPublisher(t, c, &AsyncPublisherOptions{QueueSize: 1, Workers: 1, Overflow: OverflowDropOldest})
+
+		// The first event occupies the worker, the next two contend for the
+		// single queue slot.
+		require.NoError(t, p.Publish(ctx, &CreateEventRequest{EventType: "first"}))
+		require.Eventually(t, func() bool { return p.Stats().Inflight == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, p.Publish(ctx, &CreateEventRequest{EventType: "second"}))
+		require.NoError(t, p.Publish(ctx, &CreateEventRequest{EventType: "third"}))
+
+		require.Equal(t, int64(1), p.Stats().Dropped)
+		require.Equal(t, int64(1), p.Stats().Queued)
+	})
+
+	t.Run("spill", func(t *testing.T) {
+		spool, err := OpenSpool(&SpoolOptions{Dir: t.TempDir()})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = spool.Close() })
+
+		c := newBlockedPublisherTestClient(t, OptionSpool(spool))
+		p := newTestPublisher(t, c, &AsyncPublisherOptions{QueueSize: 1, Workers: 1, Overflow: OverflowSpill})
+		require.NoError(t, p.Publish(ctx, &CreateEventRequest{EventType: "first"}))
+		require.Eventually(t, func() bool { return p.Stats().Inflight == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, p.Publish(ctx, &CreateEventRequest{EventType: "second"}))
+		require.NoError(t, p.PublishFanout(ctx, &CreateFanoutEventRequest{EventType: "third"}))
+		require.Equal(t, int64(1), p.Stats().Spilled)
+
+		var spilled []*SpoolRecord
+		_, err = spool.Drain(ctx, func(_ context.Context, rec *SpoolRecord) error {
+			spilled = append(spilled, rec)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, spilled, 1)
+		require.Equal(t, messageTypeFanout, spilled[0].Type)
+
+		var body CreateFanoutEventRequest
+		require.NoError(t, json.Unmarshal(spilled[0].Body, &body))
+		require.Equal(t, "third", body.EventType)
+	})
+
+	t.Run("spill_requires_spool", func(t *testing.T) {
+		c := New("http://localhost", "test-api-key", "test-project-id")
+		_, err := NewAsyncPublisher(c, &AsyncPublisherOptions{Overflow: OverflowSpill})
+		require.Error(t, err)
+	})
+}
+
+func TestAsyncPublisherLeavesOptionsAsIs(t *testing.T) {
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	})
+
+	opts := &AsyncPublisherOptions{MaxRetries: -1}
+	p := newTestPublisher(t, c, opts)
+	other := newTestPublisher(t, c, opts)
+
+	require.Equal(t, &AsyncPublisherOptions{MaxRetries: -1}, opts)
+	require.Equal(t, 0, p.opts.MaxRetries)
+	require.Equal(t, DefaultPublisherWorkers, other.opts.Workers)
+}
@@ -0,0 +1,337 @@
+package convoy_go
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultRateLimitDuration is the window of a rate limit that doesn't
+	// set one.
+	DefaultRateLimitDuration = time.Second
+	// DefaultRateLimitBackoff is how long calls are held after a 429 that
+	// doesn't say when to retry.
+	DefaultRateLimitBackoff = time.Second
+)
+
+// RateLimitOptions configures client-side rate limiting, which makes calls
+// wait for a token instead of tripping Convoy's limits.
+type RateLimitOptions struct {
+	// Limit is the number of API calls allowed per Duration. Zero disables
+	// the global limit.
+	Limit int
+
+	// Duration is the window of Limit and EndpointLimit. Defaults to
+	// DefaultRateLimitDuration.
+	Duration time.Duration
+
+	// Burst is the number of calls that may be made back to back. Defaults
+	// to Limit.
+	Burst int
+
+	// EndpointLimit is the number of events per Duration created for any
+	// one endpoint through Events.Create. Zero disables it.
+	EndpointLimit int
+}
+
+// OptionRateLimit limits the rate of the client's API calls. Whether or
+// not a limit is set, the client holds its calls when Convoy answers with
+// 429, or reports no requests remaining in X-RateLimit-* headers, until
+// Retry-After or X-RateLimit-Reset.
+func OptionRateLimit(opts *RateLimitOptions) func(c *Client) {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(opts)
+	}
+}
+
+// RateLimitOptionsFromProject returns options matching a project's rate
+// limit, falling back to its config's when the project has none set.
+//
+//	p, err := c.Projects.Find(ctx, projectID)
+//	c = convoy.New(baseURL, apiKey, projectID,
+//	    convoy.OptionRateLimit(convoy.RateLimitOptionsFromProject(p)))
+func RateLimitOptionsFromProject(p *ProjectResponse) *RateLimitOptions {
+	opts := &RateLimitOptions{}
+	if p == nil {
+		return opts
+	}
+
+	if p.RateLimit > 0 {
+		opts.Limit = p.RateLimit
+		opts.Duration = parseRateLimitDuration(p.RateLimitDuration)
+	} else if p.Config != nil && p.Config.RateLimit != nil {
+		opts.Limit = p.Config.RateLimit.Count
+		opts.Duration = time.Duration(p.Config.RateLimit.Duration) * time.Second
+	}
+
+	return opts
+}
+
+// parseRateLimitDuration parses a window sent as a duration string, e.g.
+// "1m", or as a number of seconds.
+func parseRateLimitDuration(s string) time.Duration {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d
+	}
+
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return time.Duration(n) * time.Second
+	}
+
+	return 0
+}
+
+type rateLimiter struct {
+	global *tokenBucket
+
+	mu          sync.Mutex
+	opts        *RateLimitOptions
+	endpoints   map[string]*tokenBucket
+	lastSweep   time.Time
+	pausedUntil time.Time
+}
+
+func newRateLimiter(opts *RateLimitOptions) *rateLimiter {
+	if opts == nil {
+		opts = &RateLimitOptions{}
+	}
+
+	if opts.Duration <= 0 {
+		opts.Duration = DefaultRateLimitDuration
+	}
+
+	l := &rateLimiter{
+		opts:      opts,
+		endpoints: map[string]*tokenBucket{},
+	}
+
+	if opts.Limit > 0 {
+		l.global = newTokenBucket(opts.Limit, opts.Burst, opts.Duration)
+	}
+
+	return l
+}
+
+// wait blocks until the client may make an API call, or ctx is done. It is
+// safe to call on a nil receiver.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	pause := time.Until(l.pausedUntil)
+	l.mu.Unlock()
+
+	if err := sleepContext(ctx, pause); err != nil {
+		return err
+	}
+
+	return l.global.wait(ctx)
+}
+
+// waitEndpoint blocks until an event may be created for endpointID, or ctx
+// is done. It is safe to call on a nil receiver.
+func (l *rateLimiter) waitEndpoint(ctx context.Context, endpointID string) error {
+	if l == nil || l.opts.EndpointLimit <= 0 || isStringEmpty(endpointID) {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	b, ok := l.endpoints[endpointID]
+	if !ok {
+		b = newTokenBucket(l.opts.EndpointLimit, 0, l.opts.Duration)
+		l.endpoints[endpointID] = b
+	}
+
+	// Buckets that have refilled are dropped, so the map only holds the
+	// endpoints that are being throttled.
+	if now.Sub(l.lastSweep) >= l.opts.Duration {
+		for id, other := range l.endpoints {
+			if other != b && other.idle(now) {
+				delete(l.endpoints, id)
+			}
+		}
+		l.lastSweep = now
+	}
+	l.mu.Unlock()
+
+	return b.wait(ctx)
+}
+
+// observe adapts to the rate limit Convoy reports in resp: a 429 holds calls
+// until Retry-After, no requests remaining holds them until
+// X-RateLimit-Reset, and the global bucket never holds more tokens than
+// remain. It is safe to call on a nil receiver.
+func (l *rateLimiter) observe(resp *http.Response) {
+	if l == nil || resp == nil {
+		return
+	}
+
+	now := time.Now()
+	var until time.Time
+
+	remaining, hasRemaining := parseHeaderInt(resp.Header.Get("X-RateLimit-Remaining"))
+	if hasRemaining {
+		l.global.limit(float64(remaining))
+		if remaining == 0 {
+			until = parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now)
+		}
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if after := parseRetryAfter(resp.Header.Get("Retry-After"), now); !after.IsZero() {
+			until = after
+		} else if until.IsZero() {
+			until = now.Add(DefaultRateLimitBackoff)
+		}
+	}
+
+	if until.IsZero() {
+		return
+	}
+
+	l.mu.Lock()
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.mu.Unlock()
+}
+
+func parseHeaderInt(v string) (int64, bool) {
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	return n, err == nil && n >= 0
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Time {
+	if n, ok := parseHeaderInt(v); ok {
+		return now.Add(time.Duration(n) * time.Second)
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return t
+	}
+
+	return time.Time{}
+}
+
+// parseRateLimitReset parses an X-RateLimit-Reset header. Convoy sends the
+// Unix time, in seconds, at which the window resets; an HTTP date is
+// accepted too. A time that has passed holds no calls.
+func parseRateLimitReset(v string, now time.Time) time.Time {
+	var reset time.Time
+	if n, ok := parseHeaderInt(v); ok {
+		reset = time.Unix(n, 0)
+	} else if t, err := http.ParseTime(v); err == nil {
+		reset = t
+	}
+
+	if !reset.After(now) {
+		return time.Time{}
+	}
+
+	return reset
+}
+
+// tokenBucket allows rate calls per second on average, and burst at once.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit, burst int, per time.Duration) *tokenBucket {
+	if burst <= 0 {
+		burst = limit
+	}
+
+	return &tokenBucket{
+		rate:   float64(limit) / per.Seconds(),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens earned since the last call. b.mu must be held.
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// wait takes a token, blocking until it's earned or ctx is done. It is safe
+// to call on a nil receiver.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	b.refill(time.Now())
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+	b.mu.Unlock()
+
+	err := sleepContext(ctx, delay)
+	if err != nil {
+		// Give the token back; the call won't be made.
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+	}
+
+	return err
+}
+
+// limit caps the bucket's tokens at n. It is safe to call on a nil receiver.
+func (b *tokenBucket) limit(n float64) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	if b.tokens > n {
+		b.tokens = n
+	}
+}
+
+// idle reports whether the bucket has refilled, so dropping it loses nothing.
+func (b *tokenBucket) idle(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	return b.tokens >= b.burst
+}
+
+// sleepContext sleeps for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
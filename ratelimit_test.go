@@ -0,0 +1,162 @@
+package convoy_go
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitSpacesCalls(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"ep-1"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(srv.URL, "test-api-key", "test-project-id",
+		OptionRateLimit(&RateLimitOptions{Limit: 1, Duration: 50 * time.Millisecond}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := c.Endpoints.Find(context.Background(), "ep-1", nil)
+		require.NoError(t, err)
+	}
+
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestRateLimitPerEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	sent := map[string]time.Time{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body CreateEventRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		mu.Lock()
+		sent[body.EndpointID+body.EventType] = time.Now()
+		mu.Unlock()
+
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"ev-1"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(srv.URL, "test-api-key", "test-project-id",
+		OptionRateLimit(&RateLimitOptions{EndpointLimit: 1, Duration: 100 * time.Millisecond}))
+
+	start := time.Now()
+	for _, ev := range []*CreateEventRequest{
+		{EndpointID: "ep-a", EventType: "1", Data: json.RawMessage(`{}`)},
+		{EndpointID: "ep-b", EventType: "1", Data: json.RawMessage(`{}`)},
+		{EndpointID: "ep-a", EventType: "2", Data: json.RawMessage(`{}`)},
+	} {
+		_, err := c.Events.Create(context.Background(), ev)
+		require.NoError(t, err)
+	}
+
+	require.Less(t, sent["ep-b1"].Sub(start), 50*time.Millisecond)
+	require.GreaterOrEqual(t, sent["ep-a2"].Sub(sent["ep-a1"]), 90*time.Millisecond)
+}
+
+func TestRateLimitHoldsCallsAfterTooManyRequests(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"status":false,"message":"rate limit exceeded"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"ep-1"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(srv.URL, "test-api-key", "test-project-id", OptionRateLimit(nil))
+
+	_, err := c.Endpoints.Find(context.Background(), "ep-1", nil)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+
+	// A call that can't wait for Retry-After isn't sent.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.Endpoints.Find(ctx, "ep-1", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, int32(1), calls.Load())
+
+	start := time.Now()
+	_, err = c.Endpoints.Find(context.Background(), "ep-1", nil)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), 800*time.Millisecond)
+}
+
+func TestRateLimitObserveHeaders(t *testing.T) {
+	l := newRateLimiter(&RateLimitOptions{Limit: 100, Duration: time.Second})
+	now := time.Now()
+
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Remaining", "2")
+	l.observe(resp)
+	require.LessOrEqual(t, l.global.tokens, 2.0)
+	require.True(t, l.pausedUntil.IsZero())
+
+	reset := now.Add(time.Minute).Unix()
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+	l.observe(resp)
+	require.Equal(t, time.Unix(reset, 0), l.pausedUntil)
+
+	// A later pause wins, an earlier one doesn't shorten it.
+	resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(10*time.Second).Unix(), 10))
+	l.observe(resp)
+	require.Equal(t, time.Unix(reset, 0), l.pausedUntil)
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Unix time in seconds, as Convoy sends it.
+	require.Equal(t, now.Add(time.Minute), parseRateLimitReset(strconv.FormatInt(now.Add(time.Minute).Unix(), 10), now).UTC())
+
+	// HTTP date.
+	require.Equal(t, now.Add(time.Minute), parseRateLimitReset(now.Add(time.Minute).Format(http.TimeFormat), now))
+
+	// Times that have passed, including small numbers that aren't epoch
+	// times, and values that aren't times hold no calls.
+	require.True(t, parseRateLimitReset(strconv.FormatInt(now.Add(-time.Second).Unix(), 10), now).IsZero())
+	require.True(t, parseRateLimitReset("30", now).IsZero())
+	require.True(t, parseRateLimitReset("soon", now).IsZero())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, now.Add(5*time.Second), parseRetryAfter("5", now))
+	require.Equal(t, now.Add(time.Minute), parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now))
+	require.True(t, parseRetryAfter("soon", now).IsZero())
+}
+
+func TestRateLimitOptionsFromProject(t *testing.T) {
+	opts := RateLimitOptionsFromProject(&ProjectResponse{RateLimit: 5000, RateLimitDuration: "1m"})
+	require.Equal(t, &RateLimitOptions{Limit: 5000, Duration: time.Minute}, opts)
+
+	opts = RateLimitOptionsFromProject(&ProjectResponse{RateLimit: 100, RateLimitDuration: "10"})
+	require.Equal(t, &RateLimitOptions{Limit: 100, Duration: 10 * time.Second}, opts)
+
+	opts = RateLimitOptionsFromProject(&ProjectResponse{Config: &ProjectConfig{
+		RateLimit: &RateLimitConfiguration{Count: 20, Duration: 60},
+	}})
+	require.Equal(t, &RateLimitOptions{Limit: 20, Duration: time.Minute}, opts)
+
+	require.Equal(t, &RateLimitOptions{}, RateLimitOptionsFromProject(nil))
+}
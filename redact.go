@@ -0,0 +1,104 @@
+package convoy_go
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// DefaultRedactKeys are the JSON keys whose values are redacted from
+	// logged request and response bodies, at any depth. The value of every
+	// "secrets" entry is redacted too.
+	DefaultRedactKeys = []string{
+		"secret", "secret_key", "password", "header_value", "client_secret",
+		"client_key", "signing_key", "access_token", "token", "auth_key",
+		"service_account", "access_key_id", "secret_access_key",
+	}
+	// DefaultRedactHeaders are the request headers whose values are redacted
+	// from logs.
+	DefaultRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", DefaultSigHeader}
+	// DefaultLogBodyLimit caps the bytes of a body that are logged.
+	DefaultLogBodyLimit = 4 << 10
+)
+
+// redactBody returns a body for logging, with the values of
+// DefaultRedactKeys replaced. When secretData is set, the response's data is
+// replaced too, for routes that return a bare credential. Bodies that
+// aren't JSON are logged as is.
+func redactBody(b []byte, secretData bool) string {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return ""
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if dec.Decode(&v) == nil {
+		if m, ok := v.(map[string]interface{}); ok && secretData && m["data"] != nil {
+			m["data"] = redacted
+		}
+		if out, err := json.Marshal(redactJSON(v, "")); err == nil {
+			b = out
+		}
+	}
+
+	if len(b) > DefaultLogBodyLimit {
+		return string(b[:DefaultLogBodyLimit]) + "..."
+	}
+
+	return string(b)
+}
+
+func redactJSON(v interface{}, parent string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, x := range t {
+			if isRedactKey(k) || (parent == "secrets" && k == "value") {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactJSON(x, k)
+		}
+	case []interface{}:
+		for i, x := range t {
+			t[i] = redactJSON(x, parent)
+		}
+	}
+
+	return v
+}
+
+func isRedactKey(k string) bool {
+	for _, key := range DefaultRedactKeys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactHeader returns a copy of h for logging, with the values of
+// DefaultRedactHeaders replaced. Authorization keeps its scheme.
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range DefaultRedactHeaders {
+		v := out.Get(name)
+		if v == "" {
+			continue
+		}
+
+		if scheme, _, ok := strings.Cut(v, " "); ok && strings.EqualFold(name, "Authorization") {
+			out.Set(name, scheme+" "+redacted)
+			continue
+		}
+		out.Set(name, redacted)
+	}
+
+	return out
+}
@@ -0,0 +1,234 @@
+package convoy_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+var DefaultReplayInterval = 5 * time.Second
+
+type skipSpoolKey struct{}
+
+// withoutSpool marks ctx so a failed send returns its error instead of being
+// spooled again; used by the replayer and the publisher's retry loop.
+func withoutSpool(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipSpoolKey{}, true)
+}
+
+// fallbackToSpool appends body to the client's spool after a send through
+// transport failed with err, and reports whether it did. Nothing is spooled
+// when no spool is configured or the caller opted out.
+func (c *Client) fallbackToSpool(ctx context.Context, transport, msgType string, body interface{}, err error) bool {
+	if c.spool == nil || ctx.Value(skipSpoolKey{}) != nil || ctx.Err() != nil {
+		return false
+	}
+
+	rec, merr := newSpoolRecord(msgType, body)
+	if merr != nil {
+		return false
+	}
+	rec.Transport = transport
+
+	serr := c.spool.Append(rec)
+	if serr != nil {
+		c.log.Errorf("error spooling %s event - %+v", msgType, serr)
+		return false
+	}
+
+	c.log.Warnf("convoy unreachable, spooled %s event - %+v", msgType, err)
+	return true
+}
+
+// errBrokerUnavailable marks a broker replay that failed because the broker
+// couldn't be reached or timed out, or isn't configured, so the record is
+// kept and retried.
+var errBrokerUnavailable = errors.New("broker unavailable")
+
+// isBrokerUnavailable reports whether a broker write failed for a reason
+// that may pass: a network error, a timeout, or an error the broker's client
+// marks temporary or retryable, e.g. Kafka's LeaderNotAvailable or an SQS
+// throttle.
+func isBrokerUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) && temporary.Temporary() {
+		return true
+	}
+
+	var retryable interface{ RetryableError() bool }
+	return errors.As(err, &retryable) && retryable.RetryableError()
+}
+
+type ReplayerOptions struct {
+	// Interval is how often Run tries to drain the spool.
+	Interval time.Duration
+
+	// OnError, if set, is called for records Convoy or a broker rejected.
+	// Rejected records are removed from the spool, since resending won't
+	// help. Records whose broker is unreachable are kept until it takes
+	// them.
+	OnError func(rec *SpoolRecord, err error)
+}
+
+// Replayer sends spooled events to Convoy in the order they were spooled,
+// through the API or the broker each was first written to.
+type Replayer struct {
+	client *Client
+	spool  *Spool
+	opts   *ReplayerOptions
+}
+
+func NewReplayer(c *Client, s *Spool, opts *ReplayerOptions) *Replayer {
+	if opts == nil {
+		opts = &ReplayerOptions{}
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultReplayInterval
+	}
+
+	return &Replayer{
+		client: c,
+		spool:  s,
+		opts:   opts,
+	}
+}
+
+// Drain replays spooled events until the spool is empty or Convoy is still
+// unreachable, and returns the number of records removed.
+func (r *Replayer) Drain(ctx context.Context) (int, error) {
+	return r.spool.Drain(withoutSpool(ctx), r.replay)
+}
+
+// Run drains the spool every Interval until ctx is done.
+func (r *Replayer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.Drain(ctx)
+		if n > 0 {
+			r.client.log.Infof("replayed %d spooled events", n)
+		}
+
+		if err != nil && ctx.Err() == nil {
+			r.client.log.Warnf("spool replay paused - %+v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Replayer) replay(ctx context.Context, rec *SpoolRecord) error {
+	err := r.send(ctx, rec)
+	if err == nil || isUnreachable(err) || errors.Is(err, errBrokerUnavailable) {
+		return err
+	}
+
+	if r.opts.OnError != nil {
+		r.opts.OnError(rec, err)
+	}
+
+	r.client.log.Errorf("dropping rejected spooled %s event - %+v", rec.Type, err)
+	return nil
+}
+
+func (r *Replayer) send(ctx context.Context, rec *SpoolRecord) error {
+	switch rec.Transport {
+	case "":
+		return r.sendAPI(ctx, rec)
+	case spoolTransportKafka:
+		return r.sendBroker(ctx, r.client.Kafka, rec)
+	case spoolTransportSQS:
+		return r.sendBroker(ctx, r.client.SQS, rec)
+	default:
+		return fmt.Errorf("unknown spooled event transport %q", rec.Transport)
+	}
+}
+
+func (r *Replayer) sendAPI(ctx context.Context, rec *SpoolRecord) error {
+	switch rec.Type {
+	case messageTypeSingle:
+		body := &CreateEventRequest{}
+		if err := json.Unmarshal(rec.Body, body); err != nil {
+			return err
+		}
+		_, err := r.client.Events.Create(ctx, body)
+		return err
+	case messageTypeFanout:
+		body := &CreateFanoutEventRequest{}
+		if err := json.Unmarshal(rec.Body, body); err != nil {
+			return err
+		}
+		_, err := r.client.Events.FanoutEvent(ctx, body)
+		return err
+	case messageTypeBroadcast:
+		body := &CreateBroadcastEventRequest{}
+		if err := json.Unmarshal(rec.Body, body); err != nil {
+			return err
+		}
+		_, err := r.client.Events.BroadcastEvent(ctx, body)
+		return err
+	case messageTypeDynamic:
+		body := &CreateDynamicEventRequest{}
+		if err := json.Unmarshal(rec.Body, body); err != nil {
+			return err
+		}
+		return r.client.Events.CreateDynamic(ctx, body)
+	default:
+		return fmt.Errorf("unknown spooled message type %q", rec.Type)
+	}
+}
+
+func (r *Replayer) sendBroker(ctx context.Context, broker BrokerService, rec *SpoolRecord) error {
+	if broker == nil {
+		return fmt.Errorf("%w: replaying a spooled %s event needs the client's %s options", errBrokerUnavailable, rec.Transport, rec.Transport)
+	}
+
+	var err error
+	switch rec.Type {
+	case messageTypeSingle:
+		body := &CreateEventRequest{}
+		if err = json.Unmarshal(rec.Body, body); err != nil {
+			return err
+		}
+		err = broker.WriteEvent(ctx, body)
+	case messageTypeFanout:
+		body := &CreateFanoutEventRequest{}
+		if err = json.Unmarshal(rec.Body, body); err != nil {
+			return err
+		}
+		err = broker.WriteFanoutEvent(ctx, body)
+	case messageTypeBroadcast:
+		body := &CreateBroadcastEventRequest{}
+		if err = json.Unmarshal(rec.Body, body); err != nil {
+			return err
+		}
+		err = broker.WriteBroadcastEvent(ctx, body)
+	default:
+		return fmt.Errorf("unknown spooled message type %q", rec.Type)
+	}
+
+	if err != nil && isBrokerUnavailable(err) {
+		return fmt.Errorf("%w: %w", errBrokerUnavailable, err)
+	}
+
+	return err
+}
@@ -4,13 +4,30 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"net/http/httputil"
+	"net/url"
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
-func postJSON(ctx context.Context, c *Client, url string, body interface{}, res interface{}) error {
+// APIError is returned when Convoy answers a request with an error status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("convoy error: %s", e.Message)
+}
+
+// requestOption adjusts a request before it is sent.
+type requestOption func(req *http.Request)
+
+func postJSON(ctx context.Context, c *Client, url string, body interface{}, res interface{}, opts ...requestOption) error {
 	buf, err := json.Marshal(body)
 	if err != nil {
 		return err
@@ -22,6 +39,10 @@ func postJSON(ctx context.Context, c *Client, url string, body interface{}, res
 		return err
 	}
 
+	for _, opt := range opts {
+		opt(req)
+	}
+
 	return doReq(c, req, res)
 }
 
@@ -61,71 +82,114 @@ func deleteResource(ctx context.Context, c *Client, url string, res interface{})
 	return doReq(c, req, res)
 }
 
-func doReq(c *Client, req *http.Request, res interface{}) error {
+func doReq(c *Client, req *http.Request, res interface{}) (err error) {
+	err = c.limiter.wait(req.Context())
+	if err != nil {
+		return err
+	}
+
+	r := newRoute(c, req)
+	req, span := c.startRequestSpan(req, r)
+
+	start := time.Now()
+	statusCode := 0
+	var reqBody, respBody []byte
+	defer func() {
+		duration := time.Since(start)
+		c.metrics.ObserveRequest(r.Resource, r.Operation, statusCode, duration)
+		endSpan(span, err)
+		c.logRequest(req, r, statusCode, duration, reqBody, respBody, err)
+	}()
+
 	req.Header.Add("Content-Type", "application/json;charset=utf-8")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 	// Pin the current API version so request/response migrations are a no-op.
 	// Older pins (e.g. 0001-01-01) make the server rewrite http_timeout and
 	// rate_limit_duration into legacy duration strings.
 	req.Header.Set("X-Convoy-Version", "2025-11-24")
 
-	// Dump before sending; afterwards the body is already consumed and the
-	// dump fails with a spurious error log on every request. Redact the
-	// credential first so debug logs shipped to shared observability
-	// systems never contain the API key.
-	auth := req.Header.Get("Authorization")
-	req.Header.Set("Authorization", "Bearer [REDACTED]")
-	dump, err := httputil.DumpRequestOut(req, true)
-	if err != nil {
-		c.log.Errorf("error dumping request payload - ", err)
+	// Copy the body before sending; afterwards it's consumed.
+	if req.GetBody != nil && logEnabled(c.log, DebugLevel) {
+		if body, err := req.GetBody(); err == nil {
+			reqBody, _ = io.ReadAll(body)
+			body.Close()
+		}
 	}
-	req.Header.Set("Authorization", auth)
 
-	c.log.Debugf("request: %q", dump)
+	req = req.WithContext(context.WithValue(req.Context(), routeContextKey{}, r))
 
-	resp, err := c.client.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
-		return fmt.Errorf("error processing request - %+v", err)
+		return fmt.Errorf("error processing request - %w", err)
 	}
+	if resp == nil {
+		return errors.New("error processing request - middleware returned no response")
+	}
+	c.limiter.observe(resp)
+	statusCode = resp.StatusCode
+	span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
+
+	defer func() {
+		err := resp.Body.Close()
+		if err != nil {
+			c.log.Errorf("error closing response body - %+v", err)
+		}
+	}()
 
-	err = parseAPIResponse(c, resp, res)
+	respBody, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return fmt.Errorf("error while reading the response bytes - %+v", err)
 	}
 
-	return nil
+	return parseAPIResponse(statusCode, respBody, res)
 }
 
-func parseAPIResponse(c *Client, resp *http.Response, resultPtr interface{}) error {
-	// Send debug logs.
-	dump, err := httputil.DumpResponse(resp, true)
-	if err != nil {
-		c.log.Errorf("error dumping response payload - ", err)
+// logRequest logs an API call at debug level. DefaultRedactHeaders, the
+// values of DefaultRedactKeys and the tokens returned by refresh_token
+// routes are redacted, so debug logs shipped to shared observability
+// systems never contain credentials.
+func (c *Client) logRequest(req *http.Request, r Route, statusCode int, duration time.Duration, reqBody, respBody []byte, err error) {
+	if !logEnabled(c.log, DebugLevel) {
+		return
 	}
 
-	c.log.Debugf("response: %q", dump)
-
-	b, err := io.ReadAll(resp.Body)
+	keyvals := []interface{}{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"status", statusCode,
+		"duration", duration,
+		"request_headers", redactHeader(req.Header),
+		"request_body", redactBody(reqBody, false),
+		"response_body", redactBody(respBody, r.Operation == "refresh_token"),
+	}
 	if err != nil {
-		return fmt.Errorf("error while reading the response bytes - %+v", err)
+		keyvals = append(keyvals, "error", err.Error())
 	}
 
-	defer func() {
-		err := resp.Body.Close()
-		if err != nil {
-			c.log.Errorf("error closing response body - ", err)
-		}
-	}()
+	logFields(c.log, DebugLevel, "convoy api call", keyvals...)
+}
 
+func parseAPIResponse(statusCode int, b []byte, resultPtr interface{}) error {
 	var response APIResponse
 
-	err = json.Unmarshal(b, &response)
+	err := json.Unmarshal(b, &response)
 	if err != nil {
+		// Proxies in front of Convoy answer outages with non-JSON pages;
+		// surface those as API errors so callers can tell them apart.
+		if invalidStatusCode(statusCode) {
+			return &APIError{StatusCode: statusCode, Message: http.StatusText(statusCode)}
+		}
 		return fmt.Errorf("error while unmarshalling the response bytes %+v ", err)
 	}
 
-	if !response.Status && invalidStatusCode(resp.StatusCode) {
-		return fmt.Errorf("convoy error: %s", response.Message)
+	if !response.Status && invalidStatusCode(statusCode) {
+		return &APIError{StatusCode: statusCode, Message: response.Message}
+	}
+
+	// Callers that need the status message, not just the data, pass the
+	// envelope itself.
+	if envelope, ok := resultPtr.(*APIResponse); ok {
+		*envelope = response
+		return nil
 	}
 
 	// Data is null for accepted-async endpoints (e.g. batchretry), so only
@@ -140,6 +204,23 @@ func parseAPIResponse(c *Client, resp *http.Response, resultPtr interface{}) err
 	return nil
 }
 
+// isUnreachable reports whether err means Convoy could not take the request
+// right now, as opposed to having rejected it.
+func isUnreachable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError ||
+			apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
 func invalidStatusCode(actual int) bool {
 	//Valid list of good HTTP response codes to expect from Convoy's API
 	expected := map[int]bool{
@@ -0,0 +1,120 @@
+package convoy_go
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Route describes an API call without its IDs, so it can name spans and
+// label metrics without blowing up their cardinality.
+type Route struct {
+	// Template is the path below the base URL with IDs replaced by
+	// placeholders, e.g. "/projects/{projectID}/endpoints/{endpointID}/pause".
+	Template string
+	// Resource is the last collection in the path, e.g. "endpoints".
+	Resource string
+	// Operation is the action in the path, e.g. "pause", or else derived
+	// from the method: "list", "get", "create", "update" or "delete".
+	Operation string
+}
+
+// routeParams names the ID that follows each collection in a path.
+var routeParams = map[string]string{
+	"projects":         "projectID",
+	"endpoints":        "endpointID",
+	"events":           "eventID",
+	"eventdeliveries":  "eventDeliveryID",
+	"deliveryattempts": "deliveryAttemptID",
+	"sources":          "sourceID",
+	"subscriptions":    "subscriptionID",
+	"portal-links":     "portalLinkID",
+	"event-types":      "eventTypeID",
+	"meta-events":      "metaEventID",
+	"filters":          "filterID",
+}
+
+// routeActions are the path segments that aren't collections or IDs.
+var routeActions = map[string]bool{
+	"activate":               true,
+	"batchreplay":            true,
+	"batchretry":             true,
+	"broadcast":              true,
+	"bulk":                   true,
+	"bulk_update":            true,
+	"countbatchreplayevents": true,
+	"deprecate":              true,
+	"dynamic":                true,
+	"expire_secret":          true,
+	"fanout":                 true,
+	"forceresend":            true,
+	"import":                 true,
+	"oauth2":                 true,
+	"onboard":                true,
+	"pause":                  true,
+	"refresh_token":          true,
+	"replay":                 true,
+	"resend":                 true,
+	"revoke":                 true,
+	"test":                   true,
+	"test_filter":            true,
+	"test_function":          true,
+	"toggle_status":          true,
+}
+
+// newRoute describes a request to the client's API.
+func newRoute(c *Client, req *http.Request) Route {
+	path := req.URL.Path
+	if base, err := url.Parse(c.baseURL); err == nil {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/"))
+	}
+
+	var r Route
+	var segments, actions []string
+	hasID := false
+	param := ""
+
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		switch {
+		case seg == "":
+			continue
+		case routeParams[seg] != "":
+			r.Resource, param, hasID = seg, routeParams[seg], false
+		case routeActions[seg]:
+			actions = append(actions, seg)
+			param = ""
+		default:
+			if param == "" {
+				param = "id"
+			}
+			seg = "{" + param + "}"
+			hasID, param = true, ""
+		}
+
+		segments = append(segments, seg)
+	}
+
+	r.Template = "/" + strings.Join(segments, "/")
+	r.Operation = strings.Join(actions, "_")
+	if r.Operation != "" {
+		return r
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		r.Operation = "list"
+		if hasID {
+			r.Operation = "get"
+		}
+	case http.MethodPost:
+		r.Operation = "create"
+	case http.MethodPut:
+		r.Operation = "update"
+	case http.MethodDelete:
+		r.Operation = "delete"
+	default:
+		r.Operation = strings.ToLower(req.Method)
+	}
+
+	return r
+}
@@ -0,0 +1,35 @@
+package convoy_go
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRoute(t *testing.T) {
+	c := New("https://convoy.example.com/api/v1", "key", "project")
+
+	tests := []struct {
+		method, path string
+		want         Route
+	}{
+		{http.MethodGet, "/api/v1/projects/p1", Route{"/projects/{projectID}", "projects", "get"}},
+		{http.MethodGet, "/api/v1/projects/p1/endpoints", Route{"/projects/{projectID}/endpoints", "endpoints", "list"}},
+		{http.MethodPost, "/api/v1/projects/p1/endpoints", Route{"/projects/{projectID}/endpoints", "endpoints", "create"}},
+		{http.MethodPut, "/api/v1/projects/p1/endpoints/ep1", Route{"/projects/{projectID}/endpoints/{endpointID}", "endpoints", "update"}},
+		{http.MethodDelete, "/api/v1/projects/p1/sources/src1", Route{"/projects/{projectID}/sources/{sourceID}", "sources", "delete"}},
+		{http.MethodPut, "/api/v1/projects/p1/endpoints/ep1/pause", Route{"/projects/{projectID}/endpoints/{endpointID}/pause", "endpoints", "pause"}},
+		{http.MethodPost, "/api/v1/projects/p1/endpoints/oauth2/test", Route{"/projects/{projectID}/endpoints/oauth2/test", "endpoints", "oauth2_test"}},
+		{http.MethodPost, "/api/v1/projects/p1/events/fanout", Route{"/projects/{projectID}/events/fanout", "events", "fanout"}},
+		{http.MethodGet, "/api/v1/projects/p1/eventdeliveries/d1/deliveryattempts/a1", Route{"/projects/{projectID}/eventdeliveries/{eventDeliveryID}/deliveryattempts/{deliveryAttemptID}", "deliveryattempts", "get"}},
+		{http.MethodPut, "/api/v1/projects/p1/portal-links/pl1/revoke", Route{"/projects/{projectID}/portal-links/{portalLinkID}/revoke", "portal-links", "revoke"}},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, "https://convoy.example.com"+tt.path+"?perPage=10", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, tt.want, newRoute(c, req), tt.path)
+	}
+}
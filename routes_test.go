@@ -2,10 +2,12 @@ package convoy_go
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -97,7 +99,7 @@ func TestDebugDumpRedactsAPIKeyButSendsRealCredential(t *testing.T) {
 	logger := &captureLogger{}
 	c := New(srv.URL, "test-api-key", "test-project-id", OptionLogger(logger))
 
-	err := c.Events.Create(context.Background(), &CreateEventRequest{
+	_, err := c.Events.Create(context.Background(), &CreateEventRequest{
 		EndpointID: "ep-1",
 		EventType:  "test.event",
 		Data:       []byte(`{"k":"v"}`),
@@ -124,7 +126,7 @@ func TestEndpointPauseUsesPut(t *testing.T) {
 func TestEventCreatePostsToEvents(t *testing.T) {
 	c, captured := newTestClient(t, `{"status":true,"message":"Event queued successfully","data":null}`)
 
-	err := c.Events.Create(context.Background(), &CreateEventRequest{
+	_, err := c.Events.Create(context.Background(), &CreateEventRequest{
 		EndpointID: "ep-1",
 		EventType:  "test.event",
 		Data:       []byte(`{"k":"v"}`),
@@ -135,3 +137,270 @@ func TestEventCreatePostsToEvents(t *testing.T) {
 	require.Equal(t, "/projects/test-project-id/events", captured.path)
 	require.Contains(t, captured.body, `"endpoint_id":"ep-1"`)
 }
+
+func TestEventCreateReturnsCreatedEvent(t *testing.T) {
+	c, _ := newTestClient(t, `{"status":true,"message":"Endpoint event created successfully","data":{"uid":"evt-1","event_type":"test.event","endpoints":["ep-1"],"idempotency_key":"key-1","is_duplicate_event":false}}`)
+
+	event, err := c.Events.Create(context.Background(), &CreateEventRequest{
+		EndpointID: "ep-1",
+		EventType:  "test.event",
+		Data:       []byte(`{"k":"v"}`),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "evt-1", event.UID)
+	require.Equal(t, []string{"ep-1"}, event.Endpoints)
+	require.Equal(t, "key-1", event.IdempotencyKey)
+}
+
+func TestEventBroadcastReturnsAcceptance(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"Broadcast event created successfully","data":null}`)
+
+	resp, err := c.Events.BroadcastEvent(context.Background(), &CreateBroadcastEventRequest{
+		EventType: "test.event",
+		Data:      []byte(`{"k":"v"}`),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "/projects/test-project-id/events/broadcast", captured.path)
+	require.True(t, resp.Accepted)
+	require.Equal(t, "Broadcast event created successfully", resp.Message)
+	require.Nil(t, resp.Event)
+}
+
+func TestEventCreateDynamicPostsToDynamicRoute(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"Dynamic event created successfully","data":null}`)
+
+	err := c.Events.CreateDynamic(context.Background(), &CreateDynamicEventRequest{
+		URL:        "https://example.com/webhooks",
+		Secret:     "endpoint-secret",
+		EventTypes: []string{"invoice.*"},
+		EventType:  "invoice.paid",
+		Data:       []byte(`{"amount":100}`),
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, http.MethodPost, captured.method)
+	require.Equal(t, "/projects/test-project-id/events/dynamic", captured.path)
+	require.JSONEq(t, `{
+		"url": "https://example.com/webhooks",
+		"secret": "endpoint-secret",
+		"event_types": ["invoice.*"],
+		"event_type": "invoice.paid",
+		"data": {"amount": 100}
+	}`, captured.body)
+}
+
+func TestEventCountAffectedUsesCountRoute(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"events count","data":{"num":42}}`)
+
+	n, err := c.Events.CountAffected(context.Background(), &BatchReplayOptions{SourceID: "src-1"})
+	require.NoError(t, err)
+	require.Equal(t, 42, n)
+
+	require.Equal(t, http.MethodGet, captured.method)
+	require.Equal(t, "/projects/test-project-id/events/countbatchreplayevents", captured.path)
+	require.Contains(t, captured.query, "sourceId=src-1")
+}
+
+func TestBatchReplayAbortsWhenConfirmDeclines(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"events count","data":{"num":1000000}}`)
+
+	var got int
+	err := c.Events.BatchReplay(context.Background(), &BatchReplayOptions{}, WithConfirm(func(count int) bool {
+		got = count
+		return false
+	}))
+	require.ErrorIs(t, err, ErrBatchDeclined)
+	require.Equal(t, 1000000, got)
+
+	// Only the count request reached the server.
+	require.Equal(t, "/projects/test-project-id/events/countbatchreplayevents", captured.path)
+}
+
+func TestEventDeliveryCountAffectedPagesThroughDeliveries(t *testing.T) {
+	var cursors []string
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("next_page_cursor")
+		cursors = append(cursors, cursor)
+
+		if cursor == "" {
+			_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"content":[{"uid":"1"},{"uid":"2"}],"pagination":{"has_next_page":true,"next_page_cursor":"c2"}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"content":[{"uid":"3"}],"pagination":{"has_next_page":false}}}`))
+	})
+
+	n, err := c.EventDeliveries.CountAffected(context.Background(), &EventDeliveryParams{Status: []string{"Failure"}})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, []string{"", "c2"}, cursors)
+}
+
+func TestEventDeliveryCountAffectedStopsAtLimit(t *testing.T) {
+	pages := 0
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("perPage"))
+		content := strings.TrimSuffix(strings.Repeat(`{"uid":"d"},`, perPage), ",")
+		_, _ = fmt.Fprintf(w, `{"status":true,"message":"ok","data":{"content":[%s],"pagination":{"has_next_page":true,"next_page_cursor":"c%d"}}}`, content, pages)
+	})
+
+	n, err := c.EventDeliveries.CountAffected(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, DeliveryCountLimit, n)
+	require.Equal(t, DeliveryCountLimit/deliveryCountPageSize, pages)
+}
+
+func TestEventDeliveryForceResendChunksAndAggregates(t *testing.T) {
+	var bodies []string
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		require.Equal(t, "/projects/test-project-id/eventdeliveries/forceresend", r.URL.Path)
+
+		switch len(bodies) {
+		case 1:
+			_, _ = w.Write([]byte(`{"status":true,"message":"1 successful, 1 failed","data":null}`))
+		case 2:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":false,"message":"event delivery not found"}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":true,"message":"1 successful, 0 failed","data":null}`))
+		}
+	})
+
+	res, err := c.EventDeliveries.ForceResend(context.Background(), []string{"d1", "d2", "d3", "d4", "d5"}, WithChunkSize(2))
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 3)
+	require.JSONEq(t, `{"ids":["d1","d2"]}`, bodies[0])
+	require.Equal(t, 2, res.Queued)
+	require.Equal(t, 1, res.Failed)
+	require.Len(t, res.Rejected, 2)
+	require.Contains(t, res.Rejected, "d3")
+	require.Contains(t, res.Rejected, "d4")
+}
+
+func TestEventDeliveryForceResendReportsFailedIDsWithSingleIDChunks(t *testing.T) {
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req forceResendRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(t, req.IDs, 1)
+
+		if req.IDs[0] == "d2" {
+			_, _ = w.Write([]byte(`{"status":true,"message":"0 successful, 1 failed","data":null}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":true,"message":"1 successful, 0 failed","data":null}`))
+	})
+
+	res, err := c.EventDeliveries.ForceResend(context.Background(), []string{"d1", "d2", "d3"}, WithChunkSize(1))
+	require.NoError(t, err)
+
+	require.Equal(t, 2, res.Queued)
+	require.Equal(t, 1, res.Failed)
+	require.Equal(t, map[string]error{"d2": ErrForceResendFailed}, res.Rejected)
+}
+
+func TestEndpointActivateUsesPost(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"endpoint activated","data":{"uid":"ep-1","status":"active"}}`)
+
+	endpoint, err := c.Endpoints.Activate(context.Background(), "ep-1")
+	require.NoError(t, err)
+	require.Equal(t, EndpointStatusActive, endpoint.Status)
+
+	require.Equal(t, http.MethodPost, captured.method)
+	require.Equal(t, "/projects/test-project-id/endpoints/ep-1/activate", captured.path)
+	require.JSONEq(t, `{}`, captured.body)
+}
+
+func TestEndpointHealthSummarizesEndpointAndFailures(t *testing.T) {
+	var deliveriesQuery string
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/test-project-id/endpoints/ep-1":
+			_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"ep-1","status":"active","cb_state":"open","failure_rate":0.75}}`))
+		case "/projects/test-project-id/eventdeliveries":
+			deliveriesQuery = r.URL.RawQuery
+			_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"content":[{"uid":"d1","status":"Failure"}],"pagination":{}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	health, err := c.Endpoints.Health(context.Background(), "ep-1")
+	require.NoError(t, err)
+
+	require.Equal(t, EndpointStatusActive, health.Status)
+	require.Equal(t, "open", health.CBState)
+	require.InDelta(t, 0.75, *health.FailureRate, 1e-9)
+	require.Len(t, health.RecentFailures, 1)
+	require.False(t, health.Healthy())
+
+	require.Contains(t, deliveriesQuery, "endpointId=ep-1")
+	require.Contains(t, deliveriesQuery, "status=Failure")
+}
+
+func TestPortalLinkRevokeUsesRevokeRoute(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"portal link revoked","data":null}`)
+
+	err := c.PortalLinks.Revoke(context.Background(), "pl-1")
+	require.NoError(t, err)
+
+	require.Equal(t, http.MethodPut, captured.method)
+	require.Equal(t, "/projects/test-project-id/portal-links/pl-1/revoke", captured.path)
+}
+
+func TestPortalLinkRefreshTokenReturnsToken(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"token refreshed","data":"new-token"}`)
+
+	token, err := c.PortalLinks.RefreshToken(context.Background(), "pl-1")
+	require.NoError(t, err)
+	require.Equal(t, "new-token", token)
+
+	require.Equal(t, http.MethodGet, captured.method)
+	require.Equal(t, "/projects/test-project-id/portal-links/pl-1/refresh_token", captured.path)
+}
+
+func TestSourceTestFunctionPostsFunctionAndPayload(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"ok","data":{"payload":{"amount":200},"log":["doubled"]}}`)
+
+	resp, err := c.Sources.TestFunction(context.Background(), &SourceFunction{
+		Type:     SourceFunctionBody,
+		Function: "function transform(payload) { payload.amount *= 2; return payload }",
+	}, []byte(`{"amount":100}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount":200}`, string(resp.Payload))
+	require.Equal(t, []string{"doubled"}, resp.Log)
+
+	require.Equal(t, http.MethodPost, captured.method)
+	require.Equal(t, "/projects/test-project-id/sources/test_function", captured.path)
+	require.JSONEq(t, `{"function":"function transform(payload) { payload.amount *= 2; return payload }","payload":{"amount":100},"type":"body"}`, captured.body)
+}
+
+func TestSubscriptionToggleUsesToggleStatusRoute(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"subscription status updated","data":{"uid":"sub-1","status":"inactive"}}`)
+
+	sub, err := c.Subscriptions.Toggle(context.Background(), "sub-1")
+	require.NoError(t, err)
+	require.Equal(t, "inactive", sub.Status)
+
+	require.Equal(t, http.MethodPut, captured.method)
+	require.Equal(t, "/projects/test-project-id/subscriptions/sub-1/toggle_status", captured.path)
+}
+
+func TestSubscriptionTestFunctionPostsToTestFunctionRoute(t *testing.T) {
+	c, captured := newTestClient(t, `{"status":true,"message":"ok","data":{"payload":{"id":1},"log":[]}}`)
+
+	resp, err := c.Subscriptions.TestFunction(context.Background(), &FunctionRequest{
+		Function: "function transform(payload) { return payload }",
+		Payload:  []byte(`{"id":1}`),
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":1}`, string(resp.Payload))
+
+	require.Equal(t, http.MethodPost, captured.method)
+	require.Equal(t, "/projects/test-project-id/subscriptions/test_function", captured.path)
+	require.JSONEq(t, `{"function":"function transform(payload) { return payload }","payload":{"id":1}}`, captured.body)
+}
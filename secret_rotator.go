@@ -0,0 +1,157 @@
+package convoy_go
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrSecretNotRotated         = errors.New("endpoint did not return the new secret")
+	ErrSecretDistribution       = errors.New("error distributing new secret")
+	ErrSecretRotationUnverified = errors.New("secret rotation could not be verified")
+	ErrSimpleSignatures         = errors.New("endpoint must use advanced signatures to rotate its secret without downtime")
+)
+
+var (
+	DefaultSecretGracePeriod = 24 * time.Hour
+	DefaultSecretLength      = 32
+)
+
+type SecretRotatorOptions struct {
+	// GracePeriod is how long the old secret stays valid. Convoy counts it
+	// in whole hours, so it is rounded up.
+	GracePeriod time.Duration
+
+	// SecretLength is the number of random bytes in a generated secret.
+	SecretLength int
+
+	// Distribute, if set, is called once Convoy signs with both secrets, to
+	// hand the new secret to the receiver before the old one expires.
+	Distribute func(ctx context.Context, rotation *SecretRotation) error
+}
+
+// SecretRotation is the outcome of a rotation. Convoy signs deliveries with
+// New and with every secret in Old until its ExpiresAt.
+type SecretRotation struct {
+	EndpointID string
+	New        Secret
+	Old        []Secret
+}
+
+// SecretRotator rolls endpoint secrets without a window where the receiver
+// rejects deliveries. That needs advanced signatures, which carry a
+// signature per secret so a receiver holding either secret accepts them;
+// endpoints with simple signatures are refused with ErrSimpleSignatures.
+type SecretRotator struct {
+	client *Client
+	opts   *SecretRotatorOptions
+}
+
+func NewSecretRotator(c *Client, opts *SecretRotatorOptions) *SecretRotator {
+	if opts == nil {
+		opts = &SecretRotatorOptions{}
+	}
+
+	if opts.GracePeriod <= 0 {
+		opts.GracePeriod = DefaultSecretGracePeriod
+	}
+
+	if opts.SecretLength <= 0 {
+		opts.SecretLength = DefaultSecretLength
+	}
+
+	return &SecretRotator{
+		client: c,
+		opts:   opts,
+	}
+}
+
+// Rotate checks the endpoint uses advanced signatures, generates a secret,
+// sets it on the endpoint while the current one
+// expires after the grace period, distributes it and then checks that Convoy
+// holds both secrets. The rotation is returned even when distribution or
+// verification fails, since the new secret is already live by then.
+func (r *SecretRotator) Rotate(ctx context.Context, endpointID string) (*SecretRotation, error) {
+	endpoint, err := r.client.Endpoints.Find(ctx, endpointID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if !endpoint.AdvancedSignatures {
+		return nil, ErrSimpleSignatures
+	}
+
+	secret, err := generateSecret(r.opts.SecretLength)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err = r.client.Endpoints.ExpireSecret(ctx, endpointID, &RollSecretRequest{
+		Expiration: graceHours(r.opts.GracePeriod),
+		Secret:     secret,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rotation, ok := newSecretRotation(endpoint, secret, time.Now())
+	if !ok {
+		return nil, ErrSecretNotRotated
+	}
+
+	if r.opts.Distribute != nil {
+		err = r.opts.Distribute(ctx, rotation)
+		if err != nil {
+			return rotation, fmt.Errorf("%w: %v", ErrSecretDistribution, err)
+		}
+	}
+
+	endpoint, err = r.client.Endpoints.Find(ctx, endpointID, nil)
+	if err != nil {
+		return rotation, fmt.Errorf("%w: %v", ErrSecretRotationUnverified, err)
+	}
+
+	current, ok := newSecretRotation(endpoint, secret, time.Now())
+	if !ok || len(current.Old) < len(rotation.Old) {
+		return rotation, ErrSecretRotationUnverified
+	}
+
+	return current, nil
+}
+
+// newSecretRotation splits the endpoint's secrets into the new one and the
+// old ones still valid at now; ok is false if the new one isn't active.
+func newSecretRotation(endpoint *EndpointResponse, secret string, now time.Time) (rotation *SecretRotation, ok bool) {
+	rotation = &SecretRotation{EndpointID: endpoint.UID}
+
+	for _, s := range endpoint.Secrets {
+		active := s.ExpiresAt.IsZero() || s.ExpiresAt.After(now)
+		switch {
+		case s.Value == secret:
+			rotation.New = s
+			ok = active
+		case active:
+			rotation.Old = append(rotation.Old, s)
+		}
+	}
+
+	return rotation, ok
+}
+
+func generateSecret(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func graceHours(d time.Duration) int {
+	return int((d + time.Hour - 1) / time.Hour)
+}
@@ -0,0 +1,94 @@
+package convoy_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newRotationTestClient(t *testing.T, keepOldSecret bool) (*Client, *RollSecretRequest) {
+	t.Helper()
+	return newSignatureRotationTestClient(t, keepOldSecret, true)
+}
+
+func newSignatureRotationTestClient(t *testing.T, keepOldSecret, advanced bool) (*Client, *RollSecretRequest) {
+	t.Helper()
+
+	expiresAt := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	rolled := &RollSecretRequest{}
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			require.Equal(t, "/projects/test-project-id/endpoints/ep-1/expire_secret", r.URL.Path)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(rolled))
+		}
+
+		secrets := `[{"uid":"s1","value":"old-secret","expires_at":"` + expiresAt + `"},{"uid":"s2","value":"` + rolled.Secret + `"}]`
+		if r.Method == http.MethodGet && !keepOldSecret && rolled.Secret != "" {
+			secrets = `[{"uid":"s2","value":"` + rolled.Secret + `"}]`
+		}
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"uid":"ep-1","advanced_signatures":` + strconv.FormatBool(advanced) + `,"secrets":` + secrets + `}}`))
+	})
+
+	return c, rolled
+}
+
+func TestSecretRotatorRotatesAndDistributes(t *testing.T) {
+	c, rolled := newRotationTestClient(t, true)
+
+	var distributed string
+	r := NewSecretRotator(c, &SecretRotatorOptions{
+		GracePeriod: 90 * time.Minute,
+		Distribute: func(ctx context.Context, rotation *SecretRotation) error {
+			distributed = rotation.New.Value
+			return nil
+		},
+	})
+
+	rotation, err := r.Rotate(context.Background(), "ep-1")
+	require.NoError(t, err)
+
+	require.Equal(t, 2, rolled.Expiration)
+	require.Len(t, rolled.Secret, 2*DefaultSecretLength)
+	require.Equal(t, rolled.Secret, distributed)
+	require.Equal(t, rolled.Secret, rotation.New.Value)
+	require.Len(t, rotation.Old, 1)
+	require.Equal(t, "old-secret", rotation.Old[0].Value)
+	require.False(t, rotation.Old[0].ExpiresAt.IsZero())
+}
+
+func TestSecretRotatorReportsUnverifiedRotation(t *testing.T) {
+	c, _ := newRotationTestClient(t, false)
+
+	rotation, err := NewSecretRotator(c, nil).Rotate(context.Background(), "ep-1")
+	require.ErrorIs(t, err, ErrSecretRotationUnverified)
+	require.NotNil(t, rotation)
+}
+
+func TestSecretRotatorReportsDistributionErrors(t *testing.T) {
+	c, _ := newRotationTestClient(t, true)
+
+	r := NewSecretRotator(c, &SecretRotatorOptions{
+		Distribute: func(ctx context.Context, rotation *SecretRotation) error {
+			return errors.New("vault unavailable")
+		},
+	})
+
+	rotation, err := r.Rotate(context.Background(), "ep-1")
+	require.ErrorIs(t, err, ErrSecretDistribution)
+	require.NotEmpty(t, rotation.New.Value)
+}
+
+func TestSecretRotatorRefusesSimpleSignatures(t *testing.T) {
+	c, rolled := newSignatureRotationTestClient(t, true, false)
+
+	rotation, err := NewSecretRotator(c, nil).Rotate(context.Background(), "ep-1")
+	require.ErrorIs(t, err, ErrSimpleSignatures)
+	require.Nil(t, rotation)
+	require.Empty(t, rolled.Secret)
+}
@@ -2,6 +2,7 @@ package convoy_go
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -12,6 +13,17 @@ var (
 	ErrNotSourceResponse     = errors.New("invalid source response")
 )
 
+// SourceService is the sources API. *Source implements it; convoymock has a
+// mock.
+type SourceService interface {
+	All(ctx context.Context, query *SourceParams) (*ListSourceResponse, error)
+	Create(ctx context.Context, body *CreateSourceRequest) (*SourceResponse, error)
+	Find(ctx context.Context, sourceId string) (*SourceResponse, error)
+	Update(ctx context.Context, sourceId string, body *CreateSourceRequest) (*SourceResponse, error)
+	Delete(ctx context.Context, sourceId string) error
+	TestFunction(ctx context.Context, fn *SourceFunction, samplePayload json.RawMessage) (*FunctionResponse, error)
+}
+
 type Source struct {
 	client *Client
 }
@@ -22,6 +34,22 @@ type CreateSourceRequest struct {
 	Provider   string         `json:"provider"`
 	IsDisabled bool           `json:"is_disabled"`
 	Verifier   VerifierConfig `json:"verifier"`
+
+	// BodyFunction and HeaderFunction are JavaScript transforms applied to
+	// every ingested event's payload and headers.
+	BodyFunction   string `json:"body_function,omitempty"`
+	HeaderFunction string `json:"header_function,omitempty"`
+
+	// CustomResponse replaces Convoy's reply to the webhook sender.
+	CustomResponse *CustomResponse `json:"custom_response,omitempty"`
+	// IdempotencyKeys are request locations, e.g. "request.header.X-Id",
+	// used to detect duplicate deliveries from the sender.
+	IdempotencyKeys []string `json:"idempotency_keys,omitempty"`
+	// EventTypeLocation is where Convoy reads the event type from.
+	EventTypeLocation string `json:"event_type_location,omitempty"`
+
+	// PubSub configures the broker a pub_sub source consumes from.
+	PubSub *PubSubConfig `json:"pub_sub,omitempty"`
 }
 
 type SourceResponse struct {
@@ -35,11 +63,113 @@ type SourceResponse struct {
 	Verifier       *VerifierConfig `json:"verifier"`
 	ProviderConfig *ProviderConfig `json:"provider_config"`
 	ForwardHeaders []string        `json:"forward_headers"`
+	URL            string          `json:"url"`
+
+	BodyFunction      string          `json:"body_function"`
+	HeaderFunction    string          `json:"header_function"`
+	CustomResponse    *CustomResponse `json:"custom_response"`
+	IdempotencyKeys   []string        `json:"idempotency_keys"`
+	EventTypeLocation string          `json:"event_type_location"`
+	PubSub            *PubSubConfig   `json:"pub_sub"`
 
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
 }
 
+type CustomResponse struct {
+	Body        string `json:"body"`
+	ContentType string `json:"content_type"`
+}
+
+type PubSubConfig struct {
+	// Type is one of "sqs", "google", "kafka" or "amqp", and selects the
+	// matching config below.
+	Type    string `json:"type"`
+	Workers int    `json:"workers,omitempty"`
+
+	Sqs    *SqsPubSubConfig    `json:"sqs,omitempty"`
+	Google *GooglePubSubConfig `json:"google,omitempty"`
+	Kafka  *KafkaPubSubConfig  `json:"kafka,omitempty"`
+	Amqp   *AmqpPubSubConfig   `json:"amqp,omitempty"`
+}
+
+type SqsPubSubConfig struct {
+	AccessKeyID   string `json:"access_key_id"`
+	SecretKey     string `json:"secret_key"`
+	DefaultRegion string `json:"default_region"`
+	QueueName     string `json:"queue_name"`
+}
+
+type GooglePubSubConfig struct {
+	ProjectID      string `json:"project_id"`
+	SubscriptionID string `json:"subscription_id"`
+	// ServiceAccount is the service account JSON key.
+	ServiceAccount []byte `json:"service_account"`
+}
+
+type KafkaPubSubConfig struct {
+	Brokers         []string   `json:"brokers"`
+	ConsumerGroupID string     `json:"consumer_group_id,omitempty"`
+	TopicName       string     `json:"topic_name"`
+	Auth            *KafkaAuth `json:"auth,omitempty"`
+}
+
+type KafkaAuth struct {
+	Type     string `json:"type"`
+	Hash     string `json:"hash,omitempty"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	TLS      bool   `json:"tls"`
+}
+
+type AmqpPubSubConfig struct {
+	Schema             string        `json:"schema"`
+	Host               string        `json:"host"`
+	Port               string        `json:"port"`
+	Queue              string        `json:"queue"`
+	Vhost              string        `json:"vhost,omitempty"`
+	DeadLetterExchange string        `json:"deadLetterExchange,omitempty"`
+	Auth               *AmqpAuth     `json:"auth,omitempty"`
+	BindExchange       *AmqpExchange `json:"bindExchange,omitempty"`
+}
+
+type AmqpAuth struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+type AmqpExchange struct {
+	Exchange   string `json:"exchange"`
+	RoutingKey string `json:"routingKey"`
+}
+
+const (
+	SourceFunctionBody   = "body"
+	SourceFunctionHeader = "header"
+)
+
+// SourceFunction is a source's body or header transform.
+type SourceFunction struct {
+	// Type is SourceFunctionBody or SourceFunctionHeader.
+	Type     string
+	Function string
+}
+
+// FunctionRequest runs a transform function against a sample payload.
+type FunctionRequest struct {
+	Function string          `json:"function"`
+	Payload  json.RawMessage `json:"payload"`
+	// Type is "body" or "header" for source functions.
+	Type string `json:"type,omitempty"`
+}
+
+type FunctionResponse struct {
+	// Payload is the transformed payload.
+	Payload json.RawMessage `json:"payload"`
+	// Log holds the function's console output.
+	Log []string `json:"log"`
+}
+
 type ListSourceResponse struct {
 	Content    []SourceResponse `json:"content"`
 	Pagination Pagination       `json:"pagination"`
@@ -162,6 +292,29 @@ func (s *Source) Delete(ctx context.Context, sourceId string) error {
 	return nil
 }
 
+// TestFunction runs fn against samplePayload on the server without saving
+// it.
+func (s *Source) TestFunction(ctx context.Context, fn *SourceFunction, samplePayload json.RawMessage) (*FunctionResponse, error) {
+	url, err := addOptions(s.generateUrl()+"/test_function", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &FunctionRequest{
+		Function: fn.Function,
+		Payload:  samplePayload,
+		Type:     fn.Type,
+	}
+
+	respPtr := &FunctionResponse{}
+	err = postJSON(ctx, s.client, url, body, respPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return respPtr, nil
+}
+
 func (s *Source) generateUrl() string {
 	return fmt.Sprintf("%s/projects/%s/sources", s.client.baseURL, s.client.projectID)
 }
@@ -0,0 +1,639 @@
+package convoy_go
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSpoolFull   = errors.New("spool is full")
+	ErrSpoolClosed = errors.New("spool is closed")
+)
+
+// Spool record transports; API records have none.
+const (
+	spoolTransportKafka = "kafka"
+	spoolTransportSQS   = "sqs"
+)
+
+const (
+	spoolSegmentExt      = ".seg"
+	spoolCursorFile      = "cursor"
+	spoolFrameHeaderSize = 8
+)
+
+var (
+	DefaultSpoolSegmentSize  int64 = 16 << 20
+	DefaultSpoolMaxSize      int64 = 1 << 30
+	DefaultSpoolSyncInterval       = time.Second
+)
+
+// SyncPolicy decides how often the spool fsyncs appended records.
+type SyncPolicy int
+
+const (
+	// SyncAlways fsyncs after every append. Nothing acknowledged is lost
+	// on a crash, at the cost of one fsync per event.
+	SyncAlways SyncPolicy = iota
+
+	// SyncInterval fsyncs at most once per SyncInterval, and in the
+	// background, so records appended before the spool goes idle are
+	// synced within SyncInterval too.
+	SyncInterval
+
+	// SyncNever leaves flushing to the operating system.
+	SyncNever
+)
+
+type SpoolOptions struct {
+	// Dir holds the segment files and the replay cursor.
+	Dir string
+
+	// SegmentSize is the size in bytes at which the spool starts a new
+	// segment file.
+	SegmentSize int64
+
+	// MaxSize caps the bytes on disk; appends past it fail with ErrSpoolFull.
+	MaxSize int64
+
+	Sync SyncPolicy
+
+	// SyncInterval is the fsync period used with the SyncInterval policy.
+	SyncInterval time.Duration
+}
+
+// SpoolRecord is an event waiting in the spool. Type is the message type
+// ("single", "fanout", "broadcast" or "dynamic"), Body the JSON create
+// request, and Transport the broker it was written to, "kafka" or "sqs", or
+// empty when it was sent through the API.
+type SpoolRecord struct {
+	Type      string          `json:"type"`
+	Transport string          `json:"transport,omitempty"`
+	Body      json.RawMessage `json:"body"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// SpoolStats is a point-in-time snapshot of a spool.
+type SpoolStats struct {
+	Segments int
+	// PendingBytes is the size of the records not yet drained.
+	PendingBytes int64
+	// SkippedBytes counts corrupt bytes skipped since the spool was opened.
+	SkippedBytes int64
+}
+
+// Spool is an append-only, on-disk log of events that could not be delivered
+// to Convoy. Records are length-prefixed and checksummed so a torn write or a
+// damaged segment only loses the affected bytes.
+type Spool struct {
+	opts *SpoolOptions
+
+	mu       sync.Mutex
+	closed   bool
+	segments []uint64
+	sizes    map[uint64]int64
+	size     int64
+	active   *os.File
+	lastSync time.Time
+	// unsynced is set while appended records wait for an fsync.
+	unsynced bool
+	stop     chan struct{}
+	cursor   spoolCursor
+	skipped  int64
+
+	// drainMu serializes Drain so records are replayed in order.
+	drainMu sync.Mutex
+}
+
+type spoolCursor struct {
+	Segment uint64 `json:"segment"`
+	Offset  int64  `json:"offset"`
+}
+
+func newSpoolRecord(msgType string, body interface{}) (*SpoolRecord, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SpoolRecord{Type: msgType, Body: b, CreatedAt: time.Now().UTC()}, nil
+}
+
+// OpenSpool opens the spool in opts.Dir, creating it if needed, and repairs
+// any torn write left at the end of the newest segment.
+func OpenSpool(opts *SpoolOptions) (*Spool, error) {
+	if opts == nil || isStringEmpty(opts.Dir) {
+		return nil, errors.New("spool directory is required")
+	}
+
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = DefaultSpoolSegmentSize
+	}
+
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultSpoolMaxSize
+	}
+
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = DefaultSpoolSyncInterval
+	}
+
+	err := os.MkdirAll(opts.Dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Spool{opts: opts, sizes: map[uint64]int64{}}
+
+	entries, err := os.ReadDir(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, spoolSegmentExt) {
+			continue
+		}
+
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, spoolSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		s.segments = append(s.segments, id)
+		s.sizes[id] = info.Size()
+	}
+	sort.Slice(s.segments, func(i, j int) bool { return s.segments[i] < s.segments[j] })
+
+	// A missing or unreadable cursor replays from the oldest segment; with
+	// idempotency keys set that only costs duplicate suppression upstream.
+	s.cursor = s.loadCursor()
+
+	for len(s.segments) > 0 && s.segments[0] < s.cursor.Segment {
+		err = os.Remove(s.segmentPath(s.segments[0]))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		delete(s.sizes, s.segments[0])
+		s.segments = s.segments[1:]
+	}
+
+	if len(s.segments) == 0 {
+		err = s.openSegment(s.cursor.Segment + 1)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = s.repairTail()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.cursor.Segment != s.segments[0] {
+		s.cursor = spoolCursor{Segment: s.segments[0]}
+	}
+
+	if s.cursor.Offset > s.sizes[s.cursor.Segment] {
+		s.cursor.Offset = s.sizes[s.cursor.Segment]
+	}
+
+	for _, id := range s.segments {
+		s.size += s.sizes[id]
+	}
+
+	if opts.Sync == SyncInterval {
+		s.stop = make(chan struct{})
+		go s.syncLoop()
+	}
+
+	return s, nil
+}
+
+// Append writes rec to the newest segment.
+func (s *Spool) Append(rec *SpoolRecord) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, spoolFrameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(payload))
+	copy(frame[spoolFrameHeaderSize:], payload)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSpoolClosed
+	}
+
+	if s.size+int64(len(frame)) > s.opts.MaxSize {
+		return ErrSpoolFull
+	}
+
+	id := s.activeID()
+	if s.sizes[id] > 0 && s.sizes[id]+int64(len(frame)) > s.opts.SegmentSize {
+		err = s.rotate()
+		if err != nil {
+			return err
+		}
+		id = s.activeID()
+	}
+
+	n, err := s.active.Write(frame)
+	s.sizes[id] += int64(n)
+	s.size += int64(n)
+	if err != nil {
+		return err
+	}
+
+	switch s.opts.Sync {
+	case SyncAlways:
+		return s.active.Sync()
+	case SyncInterval:
+		s.unsynced = true
+		if time.Since(s.lastSync) >= s.opts.SyncInterval {
+			return s.sync()
+		}
+	}
+
+	return nil
+}
+
+// sync fsyncs the active segment. s.mu must be held.
+func (s *Spool) sync() error {
+	s.lastSync = time.Now()
+	s.unsynced = false
+	return s.active.Sync()
+}
+
+// syncLoop fsyncs records left unsynced by Append every SyncInterval, until
+// the spool is closed.
+func (s *Spool) syncLoop() {
+	ticker := time.NewTicker(s.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		if !s.closed && s.unsynced {
+			_ = s.sync()
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Drain hands every pending record to fn, oldest first. A record is only
+// removed once fn returns nil; the first error stops the drain and is
+// returned, leaving that record at the head of the spool. It returns the
+// number of records removed.
+func (s *Spool) Drain(ctx context.Context, fn func(context.Context, *SpoolRecord) error) (int, error) {
+	s.drainMu.Lock()
+	defer s.drainMu.Unlock()
+
+	drained := 0
+	for {
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return drained, ErrSpoolClosed
+		}
+		seg, off := s.cursor.Segment, s.cursor.Offset
+		size, active := s.sizes[seg], seg == s.activeID()
+		s.mu.Unlock()
+
+		if off >= size {
+			if active {
+				return drained, s.compactActive(seg)
+			}
+
+			err := s.finishSegment(seg)
+			if err != nil {
+				return drained, err
+			}
+			continue
+		}
+
+		data, err := s.readSegment(seg, size)
+		if err != nil {
+			return drained, err
+		}
+
+		for off < size {
+			if err := ctx.Err(); err != nil {
+				return drained, err
+			}
+
+			payload, start, end, ok := nextSpoolFrame(data, off, s.opts.MaxSize)
+			if !ok {
+				s.skip(size - off)
+				off = size
+				break
+			}
+			s.skip(start - off)
+
+			rec := &SpoolRecord{}
+			if err := json.Unmarshal(payload, rec); err != nil {
+				s.skip(end - start)
+			} else if err := fn(ctx, rec); err != nil {
+				_ = s.advance(seg, start)
+				return drained, err
+			} else {
+				drained++
+			}
+
+			off = end
+			err = s.advance(seg, off)
+			if err != nil {
+				return drained, err
+			}
+		}
+
+		err = s.advance(seg, off)
+		if err != nil {
+			return drained, err
+		}
+	}
+}
+
+// Stats returns the spool's current size and corruption counters.
+func (s *Spool) Stats() SpoolStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SpoolStats{
+		Segments:     len(s.segments),
+		PendingBytes: s.size - s.cursor.Offset,
+		SkippedBytes: s.skipped,
+	}
+}
+
+// Close syncs and closes the active segment.
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if s.stop != nil {
+		close(s.stop)
+	}
+
+	err := s.active.Sync()
+	if cerr := s.active.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+func (s *Spool) activeID() uint64 {
+	return s.segments[len(s.segments)-1]
+}
+
+func (s *Spool) segmentPath(id uint64) string {
+	return filepath.Join(s.opts.Dir, fmt.Sprintf("%020d%s", id, spoolSegmentExt))
+}
+
+func (s *Spool) openSegment(id uint64) error {
+	f, err := os.OpenFile(s.segmentPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	if _, ok := s.sizes[id]; !ok {
+		s.segments = append(s.segments, id)
+	}
+	s.sizes[id] = info.Size()
+	s.active = f
+	return nil
+}
+
+func (s *Spool) rotate() error {
+	s.unsynced = false
+	err := s.active.Sync()
+	if cerr := s.active.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.openSegment(s.activeID() + 1)
+}
+
+// repairTail truncates whatever follows the last intact frame of the newest
+// segment, which is where a crash mid-append leaves a partial record.
+func (s *Spool) repairTail() error {
+	id := s.activeID()
+	data, err := s.readSegment(id, s.sizes[id])
+	if err != nil {
+		return err
+	}
+
+	var off, validEnd int64
+	for {
+		_, _, end, ok := nextSpoolFrame(data, off, s.opts.MaxSize)
+		if !ok {
+			break
+		}
+		off, validEnd = end, end
+	}
+
+	if validEnd < int64(len(data)) {
+		err = os.Truncate(s.segmentPath(id), validEnd)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.openSegment(id)
+}
+
+func (s *Spool) readSegment(id uint64, size int64) ([]byte, error) {
+	f, err := os.Open(s.segmentPath(id))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data := make([]byte, size)
+	n, err := io.ReadFull(f, data)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+
+	return data[:n], nil
+}
+
+func (s *Spool) finishSegment(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.removeHead(id)
+}
+
+// compactActive replaces the active segment with an empty one once every
+// record in it has been drained, so drained records stop counting toward
+// MaxSize.
+func (s *Spool) compactActive(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// An append may have landed since the drain read the segment.
+	if s.closed || id != s.activeID() || s.sizes[id] == 0 || s.cursor.Offset < s.sizes[id] {
+		return nil
+	}
+
+	err := s.rotate()
+	if err != nil {
+		return err
+	}
+
+	return s.removeHead(id)
+}
+
+// removeHead deletes the oldest segment, id, and moves the cursor to the
+// start of the next one. Callers must hold mu.
+func (s *Spool) removeHead(id uint64) error {
+	err := os.Remove(s.segmentPath(id))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	s.size -= s.sizes[id]
+	delete(s.sizes, id)
+	s.segments = s.segments[1:]
+	s.cursor = spoolCursor{Segment: s.segments[0]}
+
+	return s.saveCursor()
+}
+
+func (s *Spool) advance(id uint64, off int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cursor.Segment == id && s.cursor.Offset == off {
+		return nil
+	}
+
+	s.cursor = spoolCursor{Segment: id, Offset: off}
+	return s.saveCursor()
+}
+
+func (s *Spool) skip(n int64) {
+	if n <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	s.skipped += n
+	s.mu.Unlock()
+}
+
+func (s *Spool) loadCursor() spoolCursor {
+	var c spoolCursor
+
+	b, err := os.ReadFile(filepath.Join(s.opts.Dir, spoolCursorFile))
+	if err != nil {
+		return c
+	}
+
+	if err := json.Unmarshal(b, &c); err != nil {
+		return spoolCursor{}
+	}
+
+	return c
+}
+
+// saveCursor persists the read position with a write-and-rename so a crash
+// never leaves a half-written cursor behind. Callers must hold mu.
+func (s *Spool) saveCursor() error {
+	b, err := json.Marshal(s.cursor)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.opts.Dir, spoolCursorFile)
+	f, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(b)
+	if err == nil && s.opts.Sync == SyncAlways {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// nextSpoolFrame returns the first intact frame at or after off. Bytes that
+// don't form a frame are stepped over one at a time, so the reader resyncs
+// on the next good record after a corrupt region.
+func nextSpoolFrame(data []byte, off, maxLen int64) (payload []byte, start, end int64, ok bool) {
+	for start = off; int64(len(data))-start >= spoolFrameHeaderSize; start++ {
+		n := int64(binary.BigEndian.Uint32(data[start : start+4]))
+		end = start + spoolFrameHeaderSize + n
+		if n == 0 || n > maxLen || end > int64(len(data)) {
+			continue
+		}
+
+		payload = data[start+spoolFrameHeaderSize : end]
+		// Records are JSON objects; checking the first byte rules out most
+		// false starts before paying for the checksum.
+		if payload[0] != '{' {
+			continue
+		}
+
+		if crc32.ChecksumIEEE(payload) == binary.BigEndian.Uint32(data[start+4:start+8]) {
+			return payload, start, end, true
+		}
+	}
+
+	return nil, 0, 0, false
+}
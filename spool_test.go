@@ -0,0 +1,305 @@
+package convoy_go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func openTestSpool(t *testing.T, opts *SpoolOptions) *Spool {
+	t.Helper()
+
+	s, err := OpenSpool(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s
+}
+
+func appendEvents(t *testing.T, s *Spool, eventTypes ...string) {
+	t.Helper()
+
+	for _, et := range eventTypes {
+		rec, err := newSpoolRecord(messageTypeSingle, &CreateEventRequest{EventType: et})
+		require.NoError(t, err)
+		require.NoError(t, s.Append(rec))
+	}
+}
+
+func drainEventTypes(t *testing.T, s *Spool) []string {
+	t.Helper()
+
+	var got []string
+	_, err := s.Drain(context.Background(), func(_ context.Context, rec *SpoolRecord) error {
+		body := &CreateEventRequest{}
+		require.NoError(t, json.Unmarshal(rec.Body, body))
+		got = append(got, body.EventType)
+		return nil
+	})
+	require.NoError(t, err)
+
+	return got
+}
+
+func TestSpoolDrainsInOrderAcrossSegments(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestSpool(t, &SpoolOptions{Dir: dir, SegmentSize: 128})
+
+	appendEvents(t, s, "a", "b", "c", "d")
+	require.Greater(t, s.Stats().Segments, 1)
+
+	require.Equal(t, []string{"a", "b", "c", "d"}, drainEventTypes(t, s))
+	require.Equal(t, 1, s.Stats().Segments)
+	require.Zero(t, s.Stats().PendingBytes)
+}
+
+func TestSpoolResumesFromCursorAfterReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenSpool(&SpoolOptions{Dir: dir})
+	require.NoError(t, err)
+
+	appendEvents(t, s, "a", "b", "c")
+
+	// Fail on the second record so it stays at the head of the spool.
+	calls := 0
+	n, err := s.Drain(context.Background(), func(_ context.Context, rec *SpoolRecord) error {
+		calls++
+		if calls == 2 {
+			return context.DeadlineExceeded
+		}
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 1, n)
+	require.NoError(t, s.Close())
+
+	s = openTestSpool(t, &SpoolOptions{Dir: dir})
+	require.Equal(t, []string{"b", "c"}, drainEventTypes(t, s))
+}
+
+func TestSpoolRecoversFromCorruption(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenSpool(&SpoolOptions{Dir: dir})
+	require.NoError(t, err)
+
+	appendEvents(t, s, "a", "b", "c")
+	require.NoError(t, s.Close())
+
+	path := filepath.Join(dir, "00000000000000000001.seg")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// Flip a byte inside the first record's payload and leave a torn,
+	// half-written frame at the end of the segment.
+	data[spoolFrameHeaderSize+2] ^= 0xff
+	data = append(data, 0, 0, 1, 0, 1, 2, 3, 4, '{')
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	s = openTestSpool(t, &SpoolOptions{Dir: dir})
+	appendEvents(t, s, "d")
+
+	require.Equal(t, []string{"b", "c", "d"}, drainEventTypes(t, s))
+	require.Positive(t, s.Stats().SkippedBytes)
+}
+
+func TestSpoolEnforcesMaxSize(t *testing.T) {
+	s := openTestSpool(t, &SpoolOptions{Dir: t.TempDir(), MaxSize: 250})
+
+	appendEvents(t, s, "a")
+
+	rec, err := newSpoolRecord(messageTypeSingle, &CreateEventRequest{EventType: "b"})
+	require.NoError(t, err)
+	require.ErrorIs(t, s.Append(rec), ErrSpoolFull)
+}
+
+func TestSpoolCompactsDrainedActiveSegment(t *testing.T) {
+	s := openTestSpool(t, &SpoolOptions{Dir: t.TempDir(), MaxSize: 250})
+
+	appendEvents(t, s, "a")
+	require.Equal(t, []string{"a"}, drainEventTypes(t, s))
+
+	// The drained record no longer counts toward MaxSize.
+	appendEvents(t, s, "b")
+	require.Equal(t, []string{"b"}, drainEventTypes(t, s))
+	require.Equal(t, 1, s.Stats().Segments)
+}
+
+func TestEventCreateSpoolsWhenConvoyIsUnreachable(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+
+	var received atomic.Int64
+	s := openTestSpool(t, &SpoolOptions{Dir: t.TempDir()})
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`<html>upstream unavailable</html>`))
+			return
+		}
+
+		received.Add(1)
+		_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":null}`))
+	}, OptionSpool(s))
+
+	ctx := context.Background()
+	ev, err := c.Events.Create(ctx, &CreateEventRequest{EndpointID: "ep-1", EventType: "test.event"})
+	require.NoError(t, err)
+	require.True(t, ev.Spooled)
+
+	r := NewReplayer(c, s, nil)
+
+	// Still down: the record stays put and is not spooled a second time.
+	n, err := r.Drain(ctx)
+	require.Error(t, err)
+	require.Zero(t, n)
+
+	down.Store(false)
+	n, err = r.Drain(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, int64(1), received.Load())
+	require.Zero(t, s.Stats().PendingBytes)
+}
+
+func TestReplayerDropsRejectedEvents(t *testing.T) {
+	s := openTestSpool(t, &SpoolOptions{Dir: t.TempDir()})
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":false,"message":"invalid event type"}`))
+	})
+
+	appendEvents(t, s, "a")
+
+	var rejected []*SpoolRecord
+	r := NewReplayer(c, s, &ReplayerOptions{
+		OnError: func(rec *SpoolRecord, err error) { rejected = append(rejected, rec) },
+	})
+
+	n, err := r.Drain(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, rejected, 1)
+	require.Zero(t, s.Stats().PendingBytes)
+}
+
+// fakeBroker records the events written to it, failing while err is set.
+type fakeBroker struct {
+	err    error
+	events []string
+}
+
+func (b *fakeBroker) WriteEvent(ctx context.Context, body *CreateEventRequest) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.events = append(b.events, body.EventType)
+	return nil
+}
+
+func (b *fakeBroker) WriteFanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) error {
+	return b.WriteEvent(ctx, &CreateEventRequest{EventType: body.EventType})
+}
+
+func (b *fakeBroker) WriteBroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) error {
+	return b.WriteEvent(ctx, &CreateEventRequest{EventType: body.EventType})
+}
+
+func TestReplayerSendsBrokerEventsThroughTheirBroker(t *testing.T) {
+	var received atomic.Int64
+	s := openTestSpool(t, &SpoolOptions{Dir: t.TempDir()})
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":false,"message":"invalid event type"}`))
+	})
+
+	rec, err := newSpoolRecord(messageTypeSingle, &CreateEventRequest{EventType: "a"})
+	require.NoError(t, err)
+	rec.Transport = spoolTransportSQS
+	require.NoError(t, s.Append(rec))
+
+	r := NewReplayer(c, s, &ReplayerOptions{
+		OnError: func(rec *SpoolRecord, err error) { t.Errorf("dropped %s event: %v", rec.Transport, err) },
+	})
+
+	// Without an SQS client, or while SQS fails, the record is kept.
+	n, err := r.Drain(context.Background())
+	require.ErrorIs(t, err, errBrokerUnavailable)
+	require.Zero(t, n)
+
+	broker := &fakeBroker{err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	c.SQS = broker
+	n, err = r.Drain(context.Background())
+	require.ErrorIs(t, err, broker.err)
+	require.Zero(t, n)
+
+	broker.err = nil
+	n, err = r.Drain(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"a"}, broker.events)
+	require.Zero(t, received.Load())
+}
+
+func TestReplayerDropsBrokerRejections(t *testing.T) {
+	s := openTestSpool(t, &SpoolOptions{Dir: t.TempDir()})
+	c := newPublisherTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("broker event sent to the API")
+	})
+
+	broker := &fakeBroker{err: errors.New("message too large")}
+	c.Kafka = broker
+
+	for _, eventType := range []string{"a", "b"} {
+		rec, err := newSpoolRecord(messageTypeSingle, &CreateEventRequest{EventType: eventType})
+		require.NoError(t, err)
+		rec.Transport = spoolTransportKafka
+		require.NoError(t, s.Append(rec))
+	}
+
+	var rejected []error
+	r := NewReplayer(c, s, &ReplayerOptions{
+		OnError: func(rec *SpoolRecord, err error) {
+			rejected = append(rejected, err)
+			broker.err = nil
+		},
+	})
+
+	// The rejected head is dropped, so the record behind it is replayed.
+	n, err := r.Drain(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Len(t, rejected, 1)
+	require.NotErrorIs(t, rejected[0], errBrokerUnavailable)
+	require.Equal(t, []string{"b"}, broker.events)
+}
+
+func TestSpoolSyncsIdleRecordsInTheBackground(t *testing.T) {
+	s := openTestSpool(t, &SpoolOptions{Dir: t.TempDir(), Sync: SyncInterval, SyncInterval: 20 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		rec, err := newSpoolRecord(messageTypeSingle, &CreateEventRequest{EventType: "a"})
+		require.NoError(t, err)
+		require.NoError(t, s.Append(rec))
+	}
+
+	// The second append came within SyncInterval of the first one's fsync.
+	s.mu.Lock()
+	require.True(t, s.unsynced)
+	s.mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return !s.unsynced
+	}, time.Second, 5*time.Millisecond)
+}
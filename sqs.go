@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 type SQSOptions struct {
@@ -22,13 +23,28 @@ func newSQS(c *Client) *SQS {
 	}
 }
 
-func (s *SQS) WriteEvent(ctx context.Context, body *CreateEventRequest) error {
+func (s *SQS) WriteEvent(ctx context.Context, body *CreateEventRequest) (err error) {
 	if body.CustomHeaders == nil {
 		body.CustomHeaders = map[string]string{"x-convoy-message-type": "single"}
 	} else {
 		body.CustomHeaders["x-convoy-message-type"] = "single"
 	}
 
+	ev := *body
+	body = &ev
+
+	key, _, duplicate := s.client.idempotency.prepare(messageTypeSingle, body)
+	if duplicate {
+		return nil
+	}
+
+	ctx, span := s.client.startPublishSpan(ctx, semconv.MessagingSystemAWSSqs, s.client.sqsOpts.QueueUrl, messageTypeSingle)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	body.CustomHeaders = s.client.traceHeaders(ctx, body.CustomHeaders)
+
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
 		return err
@@ -43,18 +59,40 @@ func (s *SQS) WriteEvent(ctx context.Context, body *CreateEventRequest) error {
 	sqc := s.client.sqsOpts.Client
 	_, err = sqc.SendMessage(ctx, params)
 	if err != nil {
+		s.client.metrics.IncBrokerError("sqs", messageTypeSingle)
+		if s.client.fallbackToSpool(ctx, spoolTransportSQS, messageTypeSingle, body, err) {
+			span.RecordError(err)
+			return nil
+		}
 		return err
 	}
+
+	s.client.idempotency.remember(key, nil)
 	return nil
 }
 
-func (s *SQS) WriteFanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) error {
+func (s *SQS) WriteFanoutEvent(ctx context.Context, body *CreateFanoutEventRequest) (err error) {
 	if body.CustomHeaders == nil {
 		body.CustomHeaders = map[string]string{"x-convoy-message-type": "fanout"}
 	} else {
 		body.CustomHeaders["x-convoy-message-type"] = "fanout"
 	}
 
+	ev := *body
+	body = &ev
+
+	key, _, duplicate := s.client.idempotency.prepare(messageTypeFanout, body)
+	if duplicate {
+		return nil
+	}
+
+	ctx, span := s.client.startPublishSpan(ctx, semconv.MessagingSystemAWSSqs, s.client.sqsOpts.QueueUrl, messageTypeFanout)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	body.CustomHeaders = s.client.traceHeaders(ctx, body.CustomHeaders)
+
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
 		return err
@@ -69,18 +107,40 @@ func (s *SQS) WriteFanoutEvent(ctx context.Context, body *CreateFanoutEventReque
 	sqc := s.client.sqsOpts.Client
 	_, err = sqc.SendMessage(ctx, params)
 	if err != nil {
+		s.client.metrics.IncBrokerError("sqs", messageTypeFanout)
+		if s.client.fallbackToSpool(ctx, spoolTransportSQS, messageTypeFanout, body, err) {
+			span.RecordError(err)
+			return nil
+		}
 		return err
 	}
+
+	s.client.idempotency.remember(key, nil)
 	return nil
 }
 
-func (s *SQS) WriteBroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) error {
+func (s *SQS) WriteBroadcastEvent(ctx context.Context, body *CreateBroadcastEventRequest) (err error) {
 	if body.CustomHeaders == nil {
 		body.CustomHeaders = map[string]string{"x-convoy-message-type": "broadcast"}
 	} else {
 		body.CustomHeaders["x-convoy-message-type"] = "broadcast"
 	}
 
+	ev := *body
+	body = &ev
+
+	key, _, duplicate := s.client.idempotency.prepare(messageTypeBroadcast, body)
+	if duplicate {
+		return nil
+	}
+
+	ctx, span := s.client.startPublishSpan(ctx, semconv.MessagingSystemAWSSqs, s.client.sqsOpts.QueueUrl, messageTypeBroadcast)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	body.CustomHeaders = s.client.traceHeaders(ctx, body.CustomHeaders)
+
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
 		return err
@@ -95,7 +155,14 @@ func (s *SQS) WriteBroadcastEvent(ctx context.Context, body *CreateBroadcastEven
 	sqc := s.client.sqsOpts.Client
 	_, err = sqc.SendMessage(ctx, params)
 	if err != nil {
+		s.client.metrics.IncBrokerError("sqs", messageTypeBroadcast)
+		if s.client.fallbackToSpool(ctx, spoolTransportSQS, messageTypeBroadcast, body, err) {
+			span.RecordError(err)
+			return nil
+		}
 		return err
 	}
+
+	s.client.idempotency.remember(key, nil)
 	return nil
 }
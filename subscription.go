@@ -12,6 +12,26 @@ var (
 	ErrNotSubscriptionResponse     = errors.New("invalid subscription response")
 )
 
+// DeliveryMode is the delivery guarantee a subscription gives.
+type DeliveryMode string
+
+const (
+	DeliveryModeAtLeastOnce DeliveryMode = "at_least_once"
+	DeliveryModeAtMostOnce  DeliveryMode = "at_most_once"
+)
+
+// SubscriptionService is the subscriptions API. *Subscription implements
+// it; convoymock has a mock.
+type SubscriptionService interface {
+	All(ctx context.Context, query *SubscriptionParams) (*ListSubscriptionResponse, error)
+	Create(ctx context.Context, body *CreateSubscriptionRequest) (*SubscriptionResponse, error)
+	Find(ctx context.Context, subscriptionId string) (*SubscriptionResponse, error)
+	Update(ctx context.Context, subscriptionId string, body *CreateSubscriptionRequest) (*SubscriptionResponse, error)
+	Delete(ctx context.Context, subscriptionId string) error
+	Toggle(ctx context.Context, subscriptionId string) (*SubscriptionResponse, error)
+	TestFunction(ctx context.Context, body *FunctionRequest) (*FunctionResponse, error)
+}
+
 type Subscription struct {
 	client *Client
 }
@@ -21,9 +41,15 @@ type CreateSubscriptionRequest struct {
 	SourceID   string `json:"source_id"`
 	EndpointID string `json:"endpoint_id"`
 
-	AlertConfig  *AlertConfiguration  `json:"alert_config"`
-	RetryConfig  *RetryConfiguration  `json:"retry_config"`
-	FilterConfig *FilterConfiguration `json:"filter_config"`
+	AlertConfig     *AlertConfiguration     `json:"alert_config"`
+	RetryConfig     *RetryConfiguration     `json:"retry_config"`
+	FilterConfig    *FilterConfiguration    `json:"filter_config"`
+	RateLimitConfig *RateLimitConfiguration `json:"rate_limit_config,omitempty"`
+
+	DeliveryMode DeliveryMode `json:"delivery_mode,omitempty"`
+	// Function is a JavaScript transform applied to the payload before it's
+	// sent to the endpoint.
+	Function string `json:"function,omitempty"`
 }
 
 type AlertConfiguration struct {
@@ -53,12 +79,17 @@ type SubscriptionResponse struct {
 	Type   string `json:"type"`
 	Status string `json:"status"`
 
-	Source *SourceResponse `json:"source_metadata,omitempty"`
+	Source   *SourceResponse   `json:"source_metadata,omitempty"`
+	Endpoint *EndpointResponse `json:"endpoint_metadata,omitempty"`
 
 	// subscription config
-	AlertConfig  *AlertConfiguration  `json:"alert_config,omitempty"`
-	RetryConfig  *RetryConfiguration  `json:"retry_config,omitempty"`
-	FilterConfig *FilterConfiguration `json:"filter_config,omitempty"`
+	AlertConfig     *AlertConfiguration     `json:"alert_config,omitempty"`
+	RetryConfig     *RetryConfiguration     `json:"retry_config,omitempty"`
+	FilterConfig    *FilterConfiguration    `json:"filter_config,omitempty"`
+	RateLimitConfig *RateLimitConfiguration `json:"rate_limit_config,omitempty"`
+
+	DeliveryMode DeliveryMode `json:"delivery_mode,omitempty"`
+	Function     string       `json:"function,omitempty"`
 
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
@@ -154,6 +185,39 @@ func (s *Subscription) Delete(ctx context.Context, subscriptionId string) error
 	return nil
 }
 
+// Toggle switches a subscription between active and inactive.
+func (s *Subscription) Toggle(ctx context.Context, subscriptionId string) (*SubscriptionResponse, error) {
+	url, err := addOptions(s.generateUrl()+"/"+subscriptionId+"/toggle_status", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	respPtr := &SubscriptionResponse{}
+	err = putResource(ctx, s.client, url, nil, respPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return respPtr, nil
+}
+
+// TestFunction runs a subscription transform function against
+// body.Payload on the server without saving it.
+func (s *Subscription) TestFunction(ctx context.Context, body *FunctionRequest) (*FunctionResponse, error) {
+	url, err := addOptions(s.generateUrl()+"/test_function", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	respPtr := &FunctionResponse{}
+	err = postJSON(ctx, s.client, url, body, respPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	return respPtr, nil
+}
+
 func (s *Subscription) generateUrl() string {
 	return fmt.Sprintf("%s/projects/%s/subscriptions", s.client.baseURL, s.client.projectID)
 }
@@ -0,0 +1,120 @@
+package convoy_go
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope of the client's spans.
+const tracerName = "github.com/frain-dev/convoy-go/v2"
+
+var (
+	attrProjectID    = attribute.Key("convoy.project_id")
+	attrResource     = attribute.Key("convoy.resource")
+	attrOperation    = attribute.Key("convoy.operation")
+	attrMessageType  = attribute.Key("convoy.message_type")
+	attrErrorMessage = attribute.Key("convoy.error.message")
+)
+
+// tracePropagator carries trace context in W3C traceparent and tracestate
+// headers.
+var tracePropagator = propagation.TraceContext{}
+
+// OptionTracerProvider traces the client with OpenTelemetry: a client span
+// per API call, a producer span per Kafka or SQS write, and W3C trace
+// context in the custom headers of the events it creates, so the trace
+// continues through Convoy to the receiver; see Webhook.Middleware. A nil
+// tp uses the global provider.
+func OptionTracerProvider(tp trace.TracerProvider) func(c *Client) {
+	return func(c *Client) {
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// startRequestSpan starts the span of an API call and returns the request
+// to send with it, carrying the span's context and trace headers.
+func (c *Client) startRequestSpan(req *http.Request, r Route) (*http.Request, trace.Span) {
+	if c.tracer == nil {
+		return req, noop.Span{}
+	}
+
+	ctx, span := c.tracer.Start(req.Context(), req.Method+" "+r.Template,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.HTTPRoute(r.Template),
+			semconv.ServerAddress(req.URL.Hostname()),
+			attrProjectID.String(c.projectID),
+			attrResource.String(r.Resource),
+			attrOperation.String(r.Operation),
+		),
+	)
+
+	req = req.WithContext(ctx)
+	tracePropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return req, span
+}
+
+// startPublishSpan starts the span of a broker write.
+func (c *Client) startPublishSpan(ctx context.Context, system attribute.KeyValue, destination, msgType string) (context.Context, trace.Span) {
+	if c.tracer == nil {
+		return ctx, noop.Span{}
+	}
+
+	return c.tracer.Start(ctx, destination+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			system,
+			semconv.MessagingDestinationName(destination),
+			semconv.MessagingOperationTypePublish,
+			attrProjectID.String(c.projectID),
+			attrMessageType.String(msgType),
+		),
+	)
+}
+
+// endSpan ends a span, marking it failed when err isn't nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			span.SetAttributes(attrErrorMessage.String(apiErr.Message))
+		}
+	}
+
+	span.End()
+}
+
+// traceHeaders returns a copy of an event's custom headers, which Convoy
+// forwards to the receiver, with the trace context in ctx added. headers
+// isn't modified, so callers may share it between calls. It returns headers
+// as is when tracing is off or ctx has no span.
+func (c *Client) traceHeaders(ctx context.Context, headers map[string]string) map[string]string {
+	if c.tracer == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+		return headers
+	}
+
+	traced := make(map[string]string, len(headers)+2)
+	maps.Copy(traced, headers)
+
+	tracePropagator.Inject(ctx, propagation.MapCarrier(traced))
+	return traced
+}
@@ -0,0 +1,155 @@
+package convoy_go
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracedTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *tracetest.SpanRecorder, trace.TracerProvider) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	return New(srv.URL, "test-api-key", "test-project-id", OptionTracerProvider(tp)), spans, tp
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestTracingRecordsAPICalls(t *testing.T) {
+	var traceparent string
+	c, spans, _ := newTracedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status":false,"message":"endpoint not found"}`))
+	})
+
+	_, err := c.Endpoints.Find(context.Background(), "ep-1", nil)
+	require.Error(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+
+	span := ended[0]
+	require.Equal(t, "GET /projects/{projectID}/endpoints/{endpointID}", span.Name())
+	require.Equal(t, trace.SpanKindClient, span.SpanKind())
+	require.Equal(t, codes.Error, span.Status().Code)
+	require.Contains(t, traceparent, span.SpanContext().TraceID().String())
+
+	attrs := spanAttributes(span)
+	require.Equal(t, "/projects/{projectID}/endpoints/{endpointID}", attrs["http.route"].AsString())
+	require.Equal(t, int64(http.StatusNotFound), attrs["http.response.status_code"].AsInt64())
+	require.Equal(t, "test-project-id", attrs[attrProjectID].AsString())
+	require.Equal(t, "endpoint not found", attrs[attrErrorMessage].AsString())
+}
+
+func TestTracingPropagatesToWebhookReceivers(t *testing.T) {
+	var body []byte
+	c, spans, tp := newTracedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"status":true,"message":"Event queued","data":{"uid":"evt-1"}}`))
+	})
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "checkout")
+	_, err := c.Events.Create(ctx, &CreateEventRequest{EndpointID: "ep-1", EventType: "invoice.paid", Data: []byte(`{}`)})
+	require.NoError(t, err)
+	parent.End()
+
+	require.Contains(t, string(body), `"traceparent":"00-`+parent.SpanContext().TraceID().String())
+
+	// Convoy sends the custom headers along with the webhook.
+	wh := NewWebhook(&WebhookOpts{Secret: "secret", TracerProvider: tp})
+
+	var received trace.SpanContext
+	handler := wh.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = trace.SpanContextFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", nil)
+	tracePropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	req.Header.Set(DefaultSigHeader, "invalid")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	payload := []byte(`{"invoice":"inv-1"}`)
+	req = httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(payload))
+	tracePropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(payload)
+	req.Header.Set(DefaultSigHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, parent.SpanContext().TraceID(), received.TraceID())
+
+	webhookSpan := spans.Ended()[len(spans.Ended())-1]
+	require.Equal(t, "convoy webhook", webhookSpan.Name())
+	require.Equal(t, parent.SpanContext().TraceID(), webhookSpan.Parent().TraceID())
+}
+
+func TestTracingDoesNotModifySharedHeaders(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]bool{}
+	c, _, tp := newTracedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body CreateEventRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		received[body.CustomHeaders["traceparent"]] = true
+		mu.Unlock()
+
+		_, _ = w.Write([]byte(`{"status":true,"message":"Event queued","data":{"uid":"evt-1"}}`))
+	})
+
+	headers := map[string]string{"x-tenant": "acme"}
+	body := &CreateEventRequest{EndpointID: "ep-1", EventType: "invoice.paid", Data: []byte(`{}`), CustomHeaders: headers}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, span := tp.Tracer("test").Start(context.Background(), "checkout")
+			defer span.End()
+
+			_, err := c.Events.Create(ctx, body)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, map[string]string{"x-tenant": "acme"}, headers)
+	require.Equal(t, headers, body.CustomHeaders)
+	require.Len(t, received, 8)
+}
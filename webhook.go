@@ -1,6 +1,7 @@
 package convoy_go
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/sha512"
@@ -14,6 +15,11 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -48,6 +54,11 @@ type WebhookOpts struct {
 	Encoding  EncodingType
 	Hash      string
 	Tolerance time.Duration
+	// TracerProvider makes Middleware start a span for each webhook, as a
+	// child of the trace the sender propagated.
+	TracerProvider trace.TracerProvider
+	// Metrics counts verifications by result.
+	Metrics Metrics
 }
 
 type EncodingType string
@@ -75,6 +86,10 @@ func NewWebhook(opts *WebhookOpts) *Webhook {
 		opts.SigHeader = DefaultSigHeader
 	}
 
+	if opts.Metrics == nil {
+		opts.Metrics = NopMetrics{}
+	}
+
 	return &Webhook{opts}
 }
 
@@ -86,14 +101,61 @@ func (w *Webhook) VerifyRequest(r *http.Request) error {
 
 	header := r.Header.Get(w.opts.SigHeader)
 	if isStringEmpty(header) {
-		return ErrInvalidHeader
+		return w.record(ErrInvalidHeader)
 	}
 
-	return w.verify(body, header)
+	return w.record(w.verify(body, header))
 }
 
 func (w *Webhook) VerifyPayload(b []byte, header string) error {
-	return w.verify(b, header)
+	return w.record(w.verify(b, header))
+}
+
+// Middleware verifies webhooks before passing them on to next, answering
+// 401 to those whose signature doesn't verify. The request context carries
+// the W3C trace context the sender added to the event's custom headers, so
+// next's spans join the sender's trace.
+func (w *Webhook) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		ctx := tracePropagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		span := trace.SpanFromContext(ctx)
+		if w.opts.TracerProvider != nil {
+			ctx, span = w.opts.TracerProvider.Tracer(tracerName).Start(ctx, "convoy webhook",
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(semconv.HTTPRequestMethodKey.String(r.Method)),
+			)
+			defer span.End()
+		}
+		r = r.WithContext(ctx)
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(rw, "error reading webhook body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		err = ErrInvalidHeader
+		if header := r.Header.Get(w.opts.SigHeader); !isStringEmpty(header) {
+			err = w.verify(body, header)
+		}
+		w.record(err)
+
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			http.Error(rw, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// record reports a verification's result to Metrics and returns err.
+func (w *Webhook) record(err error) error {
+	w.opts.Metrics.IncWebhookVerification(verificationResult(err))
+	return err
 }
 
 func (w *Webhook) verify(body []byte, header string) error {